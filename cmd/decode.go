@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"github.com/kubetrail/bip32/pkg/flags"
 	"github.com/kubetrail/bip32/pkg/run"
 	"github.com/spf13/cobra"
 )
@@ -31,4 +32,25 @@ var decodeCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(decodeCmd)
+	f := decodeCmd.Flags()
+
+	f.String(flags.Network, flags.NetworkMainnet, "Network of hex encoded pub key: mainnet or testnet")
+
+	_ = decodeCmd.RegisterFlagCompletionFunc(
+		flags.Network,
+		func(
+			cmd *cobra.Command,
+			args []string,
+			toComplete string,
+		) (
+			[]string,
+			cobra.ShellCompDirective,
+		) {
+			return []string{
+					flags.NetworkMainnet,
+					flags.NetworkTestnet,
+				},
+				cobra.ShellCompDirectiveDefault
+		},
+	)
 }
@@ -25,4 +25,5 @@ func init() {
 	f := deriveCmd.Flags()
 
 	f.String(flags.DerivationPath, "m", "Relative chain Derivation path")
+	f.String(flags.CoinType, "", "Coin type of keys sharing key versions with btc, such as zec or ltc")
 }
@@ -47,6 +47,7 @@ func init() {
 	f.String(flags.Network, flags.NetworkMainnet, "Network: mainnet or testnet")
 	f.String(flags.AddrType, keys.AddrTypeP2pkhOrP2sh, "Script type")
 	f.Bool(flags.ShowAllKeys, false, "Show all keys")
+	f.String(flags.CoinType, keys.CoinTypeBtc, "Coin type: btc, zec, ltc, doge or dash")
 
 	_ = genCmd.RegisterFlagCompletionFunc(
 		flags.Network,
@@ -66,6 +67,27 @@ func init() {
 		},
 	)
 
+	_ = genCmd.RegisterFlagCompletionFunc(
+		flags.CoinType,
+		func(
+			cmd *cobra.Command,
+			args []string,
+			toComplete string,
+		) (
+			[]string,
+			cobra.ShellCompDirective,
+		) {
+			return []string{
+					keys.CoinTypeBtc,
+					keys.CoinTypeZec,
+					keys.CoinTypeLtc,
+					keys.CoinTypeDoge,
+					keys.CoinTypeDash,
+				},
+				cobra.ShellCompDirectiveDefault
+		},
+	)
+
 	_ = genCmd.RegisterFlagCompletionFunc(
 		flags.MnemonicLanguage,
 		func(
@@ -136,11 +158,14 @@ func init() {
 					keys.AddrTypeBip44,
 					keys.AddrTypeBip49,
 					keys.AddrTypeBip84,
+					keys.AddrTypeBip86,
+					keys.AddrTypeTaproot,
 					keys.AddrTypeP2pkhOrP2sh,
 					keys.AddrTypeP2wpkhP2sh,
 					keys.AddrTypeP2wshP2sh,
 					keys.AddrTypeP2wpkh,
 					keys.AddrTypeP2wsh,
+					keys.AddrTypeP2tr,
 				},
 				cobra.ShellCompDirectiveDefault
 		},
@@ -9,6 +9,7 @@ const (
 	MnemonicLanguage       = "mnemonic-language"
 	AddrType               = "addr-type"
 	ShowAllKeys            = "show-all-keys"
+	CoinType               = "coin-type"
 )
 
 const (
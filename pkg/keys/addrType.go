@@ -7,6 +7,7 @@ const (
 	AddrTypeP2wshP2sh   = "p2wsh-p2sh"    // mainnet: [Ypub, Yprv], testnet: [Upub, Uprv]
 	AddrTypeP2wpkh      = "p2wpkh"        // mainnet: [zpub, zprv], testnet: [vpub, vprv]
 	AddrTypeP2wsh       = "p2wsh"         // mainnet: [Zpub, Zprv], testnet: [Vpub, Vprv]
+	AddrTypeP2tr        = "p2tr"          // mainnet: [xpub, xprv], testnet: [tpub, tprv]
 
 	AddrTypeLegacy           = "legacy"            // same as AddrTypeP2pkhOrP2sh, xpub, xprv etc.
 	AddrTypeP2sh             = "p2sh"              // same as AddrTypeP2wpkhP2sh, ypub, yprv etc.
@@ -17,8 +18,27 @@ const (
 	AddrTypeBip44            = "bip44"             // same as AddrTypeLegacy xpub, xprv etc.
 	AddrTypeBip49            = "bip49"             // same as AddrTypeSegWitCompatible ypub, yprv etc.
 	AddrTypeBip84            = "bip84"             // same as AddrTypeSegWitNative zpub, zprv etc.
+	AddrTypeTaproot          = "taproot"           // same as AddrTypeP2tr
+	AddrTypeBip86            = "bip86"             // same as AddrTypeP2tr
 )
 
+// normalizeAddrType maps addr type aliases to their canonical script types.
+// Unknown addr types are returned unchanged.
+func normalizeAddrType(addrType string) string {
+	switch addrType {
+	case AddrTypeLegacy, AddrTypeBip44, AddrTypeBip32:
+		return AddrTypeP2pkhOrP2sh
+	case AddrTypeP2sh, AddrTypeSegWitCompatible, AddrTypeBip49:
+		return AddrTypeP2wpkhP2sh
+	case AddrTypeSegWitNative, AddrTypeBech32, AddrTypeBip84:
+		return AddrTypeP2wpkh
+	case AddrTypeTaproot, AddrTypeBip86:
+		return AddrTypeP2tr
+	default:
+		return addrType
+	}
+}
+
 // key versions
 const (
 	xpub = "0488b21e"
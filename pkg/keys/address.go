@@ -0,0 +1,408 @@
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// AddressesFromWIF generates addresses for the single key of a wif formatted
+// private key for each of the input script types, such as legacy,
+// segwit-compatible, segwit-native and taproot. No child derivation is
+// performed since wif carries no chain code. All supported script types
+// are used when no script types are provided. Addresses are keyed by script
+// type as provided in the input. Uncompressed keys only have p2pkh addresses,
+// since segwit and taproot outputs require compressed pub keys.
+func AddressesFromWIF(wifString string, scriptTypes []string) (map[string]string, error) {
+	wif, err := btcutil.DecodeWIF(wifString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode wif: %w", err)
+	}
+
+	_, params, ok := wifNetwork(wif)
+	if !ok {
+		return nil, fmt.Errorf("detected network is not supported, only btc mainnet and testnet keys are supported")
+	}
+
+	if len(scriptTypes) == 0 {
+		scriptTypes = []string{AddrTypeP2pkhOrP2sh, AddrTypeP2wpkhP2sh, AddrTypeP2wpkh, AddrTypeP2tr}
+		if !wif.CompressPubKey {
+			scriptTypes = scriptTypes[:1]
+		}
+	}
+
+	serializedPubKey := wif.SerializePubKey()
+	addresses := make(map[string]string)
+	for _, scriptType := range scriptTypes {
+		if !wif.CompressPubKey && normalizeAddrType(strings.ToLower(scriptType)) != AddrTypeP2pkhOrP2sh {
+			return nil, fmt.Errorf("script type %s requires a compressed pub key, wif is uncompressed", scriptType)
+		}
+
+		addr, err := scriptTypeAddress(serializedPubKey, scriptType, params)
+		if err != nil {
+			return nil, err
+		}
+		addresses[scriptType] = addr
+	}
+
+	return addresses, nil
+}
+
+// AddressesFromPubKeyHash generates addresses of a 20 byte pub key hash, i.e.,
+// hash160 of a serialized pub key, for each of the input script types, such as
+// legacy, segwit-compatible and segwit-native, without requiring the pub key.
+// Taproot addresses commit to the pub key itself and cannot be generated from
+// its hash. All supported script types are used when no script types are
+// provided. Addresses are keyed by script type as provided in the input.
+func AddressesFromPubKeyHash(hash160 []byte, network string, scriptTypes []string) (map[string]string, error) {
+	if len(hash160) != 20 {
+		return nil, fmt.Errorf("pub key hash must be 20 bytes long, found %d bytes", len(hash160))
+	}
+
+	params, ok := netParams[normalizeNetwork(network)]
+	if !ok {
+		return nil, fmt.Errorf("invalid or unsupported network: %s. allowed networks are %v", network,
+			[]string{NetworkTypeMainnet, NetworkTypeTestnet},
+		)
+	}
+
+	if len(scriptTypes) == 0 {
+		scriptTypes = []string{AddrTypeP2pkhOrP2sh, AddrTypeP2wpkhP2sh, AddrTypeP2wpkh}
+	}
+
+	addresses := make(map[string]string)
+	for _, scriptType := range scriptTypes {
+		addr, err := pubKeyHashAddress(hash160, scriptType, params)
+		if err != nil {
+			return nil, err
+		}
+		addresses[scriptType] = addr
+	}
+
+	return addresses, nil
+}
+
+// scriptTypeAddress generates single key address of a script type
+func scriptTypeAddress(serializedPubKey []byte, scriptType string, params *chaincfg.Params) (string, error) {
+	if normalizeAddrType(strings.ToLower(scriptType)) == AddrTypeP2tr {
+		addr, err := taprootAddress(serializedPubKey, params)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate taproot address: %w", err)
+		}
+		return addr, nil
+	}
+
+	return pubKeyHashAddress(btcutil.Hash160(serializedPubKey), scriptType, params)
+}
+
+// pubKeyHashAddress generates single key address of a script type
+// committing to pub key hash
+func pubKeyHashAddress(pubKeyHash []byte, scriptType string, params *chaincfg.Params) (string, error) {
+	switch normalizeAddrType(strings.ToLower(scriptType)) {
+	case AddrTypeP2pkhOrP2sh:
+		addressPubKeyHash, err := btcutil.NewAddressPubKeyHash(pubKeyHash, params)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate new address pub key hash: %w", err)
+		}
+		return addressPubKeyHash.EncodeAddress(), nil
+	case AddrTypeP2wpkhP2sh:
+		addressWitnessPubKeyHash, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate new address witness pub key hash: %w", err)
+		}
+
+		serializedScript, err := txscript.PayToAddrScript(addressWitnessPubKeyHash)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate pay to addr script: %w", err)
+		}
+
+		addressScriptHash, err := btcutil.NewAddressScriptHash(serializedScript, params)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate new address script hash: %w", err)
+		}
+		return addressScriptHash.EncodeAddress(), nil
+	case AddrTypeP2wpkh:
+		addressWitnessPubKeyHash, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate new address witness pub key hash: %w", err)
+		}
+		return addressWitnessPubKeyHash.EncodeAddress(), nil
+	case AddrTypeP2tr:
+		return "", fmt.Errorf("taproot address cannot be generated from pub key hash")
+	default:
+		return "", fmt.Errorf("invalid or unsupported single key script type: %s", scriptType)
+	}
+}
+
+// TypedAddress is an address labeled with its script type
+type TypedAddress struct {
+	Type    string `json:"type,omitempty" yaml:"type,omitempty"`
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+}
+
+// typedAddressLabels is the ordered list of single key script types
+// and their labels as listed by Key.Addresses
+var typedAddressLabels = []struct {
+	scriptType string
+	label      string
+}{
+	{scriptType: AddrTypeP2pkhOrP2sh, label: "p2pkh"},
+	{scriptType: AddrTypeP2wpkhP2sh, label: "p2sh-p2wpkh"},
+	{scriptType: AddrTypeP2wpkh, label: "p2wpkh"},
+	{scriptType: AddrTypeP2tr, label: "p2tr"},
+}
+
+// Addresses returns single key addresses of the key labeled with their
+// script types, i.e., p2pkh, p2sh-p2wpkh, p2wpkh and p2tr, using network
+// params the key was derived with, including config overrides. Only p2pkh
+// address is returned for coins and networks not supporting segwit and
+// for uncompressed pub keys. Multisig keys and keys without a pub key have
+// no single key addresses.
+func (k *Key) Addresses() []TypedAddress {
+	switch k.AddrType {
+	case AddrTypeP2wshP2sh, AddrTypeP2wsh:
+		return nil
+	}
+
+	pubKeyBytes, err := hex.DecodeString(k.PubKeyHex)
+	if err != nil || len(pubKeyBytes) == 0 {
+		return nil
+	}
+
+	params := k.chainParams()
+	if params == nil || !supportsSegWit(k.CoinType, params) {
+		if len(k.Addr) == 0 {
+			return nil
+		}
+		return []TypedAddress{{Type: typedAddressLabels[0].label, Address: k.Addr}}
+	}
+
+	pub, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil
+	}
+
+	// uncompressed pub keys only have p2pkh addresses
+	if !k.IsCompressed {
+		addr, err := scriptTypeAddress(pub.SerializeUncompressed(), AddrTypeP2pkhOrP2sh, params)
+		if err != nil {
+			return nil
+		}
+		return []TypedAddress{{Type: typedAddressLabels[0].label, Address: addr}}
+	}
+
+	var addresses []TypedAddress
+	for _, typedAddressLabel := range typedAddressLabels {
+		addr, err := scriptTypeAddress(pub.SerializeCompressed(), typedAddressLabel.scriptType, params)
+		if err != nil {
+			continue
+		}
+		addresses = append(addresses, TypedAddress{Type: typedAddressLabel.label, Address: addr})
+	}
+
+	return addresses
+}
+
+// P2pkhAddresses returns p2pkh addresses of the key for both compressed and
+// uncompressed serializations of its pub key. Early wallets used uncompressed
+// pub keys, therefore, funds of the same private key may have been sent to
+// either of these addresses.
+func (k *Key) P2pkhAddresses() (compressed, uncompressed string, err error) {
+	pubKeyBytes, err := k.PubKeyBytes()
+	if err != nil {
+		return "", "", err
+	}
+
+	pub, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse pubkey: %w", err)
+	}
+
+	coinType := k.CoinType
+	if len(coinType) == 0 {
+		coinType = CoinTypeBtc
+	}
+
+	network := normalizeNetwork(k.Network)
+	params := k.chainParams()
+	if params == nil {
+		return "", "", fmt.Errorf("%w for coin type %s on %s", ErrMissingNetworkParams, coinType, network)
+	}
+
+	addresses := make([]string, 2)
+	for i, serializedPubKey := range [][]byte{pub.SerializeCompressed(), pub.SerializeUncompressed()} {
+		// zcash transparent addresses use two byte prefixes
+		if coinType == CoinTypeZec {
+			prefix := zecAddrPrefixes[network]
+			addresses[i] = base58.CheckEncode(
+				append(append([]byte{}, prefix[1:]...), btcutil.Hash160(serializedPubKey)...),
+				prefix[0],
+			)
+			continue
+		}
+
+		addresses[i], err = scriptTypeAddress(serializedPubKey, AddrTypeP2pkhOrP2sh, params)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return addresses[0], addresses[1], nil
+}
+
+// ValidateAddress validates a single key or script hash address of a
+// supported coin and network, i.e., base58check encoded p2pkh and p2sh
+// addresses and bech32 or bech32m encoded segwit addresses. Checksum failures
+// of otherwise well formed addresses wrap ErrAddressChecksum, since these
+// most likely indicate a single character typo, whereas other failures
+// indicate that the input is not an address of a supported format.
+func ValidateAddress(address string) error {
+	if err := checkInputLength(address); err != nil {
+		return err
+	}
+
+	if _, ok := zecAddrNetwork(address); ok {
+		return nil
+	}
+
+	if hrp, ok := segWitHRP(address); ok {
+		return validateSegWitAddress(address, hrp)
+	}
+
+	if !IsValidBase58String(address) {
+		return fmt.Errorf("address is neither base58 nor bech32 encoded")
+	}
+
+	decoded, version, err := base58.CheckDecode(address)
+	if err != nil {
+		if errors.Is(err, base58.ErrChecksum) {
+			return fmt.Errorf("%w, likely a single character typo", ErrAddressChecksum)
+		}
+		return fmt.Errorf("failed to decode address: %w", err)
+	}
+
+	// p2pkh and p2sh addresses carry a 20 byte hash160
+	if len(decoded) != 20 {
+		return fmt.Errorf("invalid address payload length %d, expected 20 bytes", len(decoded))
+	}
+
+	for _, params := range addressParams() {
+		if version == params.PubKeyHashAddrID || version == params.ScriptHashAddrID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown address version %02x", version)
+}
+
+// addressParams returns chain params of btc networks and registered coins
+func addressParams() []*chaincfg.Params {
+	params := make([]*chaincfg.Params, 0, len(addrNetworks))
+	for _, addrNetwork := range addrNetworks {
+		params = append(params, addrNetwork.params)
+	}
+
+	for _, p := range registeredParams() {
+		params = append(params, p...)
+	}
+
+	return params
+}
+
+// segWitHRP returns the human readable part of an address if it is
+// one of the segwit hrp of supported networks
+func segWitHRP(address string) (string, bool) {
+	i := strings.LastIndexByte(address, '1')
+	if i < 1 {
+		return "", false
+	}
+
+	hrp := strings.ToLower(address[:i])
+	for _, params := range addressParams() {
+		if len(params.Bech32HRPSegwit) > 0 && hrp == params.Bech32HRPSegwit {
+			return hrp, true
+		}
+	}
+
+	return "", false
+}
+
+// validateSegWitAddress validates bech32 and bech32m checksums and
+// witness program of a segwit address
+// https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki
+func validateSegWitAddress(address, hrp string) error {
+	if address != strings.ToLower(address) && address != strings.ToUpper(address) {
+		return fmt.Errorf("bech32 address cannot be mixed case")
+	}
+
+	data := strings.ToLower(address)[len(hrp)+1:]
+	if len(data) < 7 {
+		return fmt.Errorf("bech32 address is too short")
+	}
+
+	values := make([]byte, len(data))
+	for i := range data {
+		v := strings.IndexByte(bech32Charset, data[i])
+		if v < 0 {
+			return fmt.Errorf("invalid bech32 character %q at position %d", data[i], len(hrp)+1+i)
+		}
+		values[i] = byte(v)
+	}
+
+	polymod := bech32Polymod(append(bech32HrpExpand(hrp), values...))
+	if polymod != 1 && polymod != bech32mConst {
+		return fmt.Errorf("%w, likely a single character typo", ErrAddressChecksum)
+	}
+
+	witnessVersion := values[0]
+	program, err := bech32.ConvertBits(values[1:len(values)-6], 5, 8, false)
+	if err != nil {
+		return fmt.Errorf("invalid witness program: %w", err)
+	}
+
+	switch {
+	case witnessVersion > 16:
+		return fmt.Errorf("invalid witness version %d", witnessVersion)
+	case witnessVersion == 0 && polymod != 1,
+		witnessVersion > 0 && polymod != bech32mConst:
+		return fmt.Errorf("witness version %d is encoded with wrong bech32 variant", witnessVersion)
+	case witnessVersion == 0 && len(program) != 20 && len(program) != 32,
+		len(program) < 2 || len(program) > 40:
+		return fmt.Errorf("invalid witness program length %d for witness version %d", len(program), witnessVersion)
+	}
+
+	return nil
+}
+
+// RedeemScriptP2SHP2WPKH returns redeem script of nested segwit p2sh-p2wpkh
+// address of the key, i.e., OP_0 followed by a push of 20 byte pub key hash,
+// which goes in the scriptSig when spending nested segwit outputs
+func RedeemScriptP2SHP2WPKH(k *Key) ([]byte, error) {
+	pubKeyBytes, err := k.PubKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pubkey: %w", err)
+	}
+
+	script, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(btcutil.Hash160(pub.SerializeCompressed())).
+		Script()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build redeem script: %w", err)
+	}
+
+	return script, nil
+}
@@ -0,0 +1,358 @@
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+)
+
+// test vectors of BIP-44, BIP-49, BIP-84 and BIP-86 for mnemonic abandon ... about
+// https://github.com/bitcoin/bips/blob/master/bip-0049.mediawiki#test-vectors
+// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+func TestKey_Addresses(t *testing.T) {
+	seed := TestSeed()
+
+	tests := []struct {
+		network        string
+		addrType       string
+		label          string
+		derivationPath string
+		addr           string
+	}{
+		{NetworkTypeMainnet, AddrTypeLegacy, "p2pkh", "m/44h/0h/0h/0/0", "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA"},
+		{NetworkTypeMainnet, AddrTypeSegWitCompatible, "p2sh-p2wpkh", "m/49h/0h/0h/0/0", "37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf"},
+		{NetworkTypeMainnet, AddrTypeSegWitNative, "p2wpkh", "m/84h/0h/0h/0/0", "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"},
+		{NetworkTypeMainnet, AddrTypeTaproot, "p2tr", "m/86h/0h/0h/0/0", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
+		{NetworkTypeTestnet, AddrTypeLegacy, "p2pkh", "m/44h/1h/0h/0/0", "mkpZhYtJu2r87Js3pDiWJDmPte2NRZ8bJV"},
+		{NetworkTypeTestnet, AddrTypeSegWitCompatible, "p2sh-p2wpkh", "m/49h/1h/0h/0/0", "2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2"},
+		{NetworkTypeTestnet, AddrTypeSegWitNative, "p2wpkh", "m/84h/1h/0h/0/0", "tb1q6rz28mcfaxtmd6v789l9rrlrusdprr9pqcpvkl"},
+		{NetworkTypeTestnet, AddrTypeTaproot, "p2tr", "m/86h/1h/0h/0/0", "tb1p8wpt9v4frpf3tkn0srd97pksgsxc5hs52lafxwru9kgeephvs7rqlqt9zj"},
+	}
+
+	for _, test := range tests {
+		key, err := New(
+			&Config{
+				Seed:           seed,
+				Network:        test.network,
+				DerivationPath: test.derivationPath,
+				AddrType:       AddrTypeLegacy,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var addr string
+		for _, typedAddress := range key.Addresses() {
+			if typedAddress.Type == test.label {
+				addr = typedAddress.Address
+			}
+		}
+
+		if addr != test.addr {
+			t.Fatal("expected", test.addr, ", got", addr, "for", test.label, "on", test.network)
+		}
+
+		// taproot keys are not generated via New
+		if test.addrType == AddrTypeTaproot {
+			continue
+		}
+
+		key, err = New(
+			&Config{
+				Seed:           seed,
+				Network:        test.network,
+				DerivationPath: test.derivationPath,
+				AddrType:       test.addrType,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key.Addr != test.addr {
+			t.Fatal("expected", test.addr, ", got", key.Addr, "for", test.addrType, "on", test.network)
+		}
+	}
+}
+
+// testnet native segwit addresses must be encoded with tb hrp
+// regardless of the code path producing them
+func TestTestnetBech32(t *testing.T) {
+	const expected = "tb1q6rz28mcfaxtmd6v789l9rrlrusdprr9pqcpvkl"
+
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeTestnet,
+			DerivationPath: "m/84h/1h/0h/0/0",
+			AddrType:       AddrTypeSegWitNative,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeTestnet,
+			DerivationPath: "m/84h/1h/0h",
+			AddrType:       AddrTypeSegWitNative,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	derived, err := Derive(account.XPub, "m/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ranged, err := DeriveRange(account.XPub, 0, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeExtendedKey(key.XPrv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addresses, err := AddressesFromWIF(key.PrvKeyWif, []string{AddrTypeP2wpkh})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, addr := range map[string]string{
+		"New":               key.Addr,
+		"PrimaryAddress":    key.PrimaryAddress,
+		"Derive":            derived.Addr,
+		"DeriveRange":       ranged[0].Addr,
+		"DecodeExtendedKey": decoded.Addr,
+		"AddressesFromWIF":  addresses[AddrTypeP2wpkh],
+	} {
+		if addr != expected {
+			t.Fatal(name, "expected", expected, ", got", addr)
+		}
+	}
+}
+
+func TestRedeemScriptP2SHP2WPKH(t *testing.T) {
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/49h/0h/0h/0/0",
+			AddrType:       AddrTypeSegWitCompatible,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script, err := RedeemScriptP2SHP2WPKH(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(script) != 22 || script[0] != 0x00 || script[1] != 0x14 {
+		t.Fatal("expected OP_0 <20 byte pub key hash>, got", hex.EncodeToString(script))
+	}
+
+	addr, err := btcutil.NewAddressScriptHash(script, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if addr.EncodeAddress() != key.Addr {
+		t.Fatal("expected redeem script hash address", key.Addr, ", got", addr.EncodeAddress())
+	}
+
+	if _, err := RedeemScriptP2SHP2WPKH(&Key{}); err == nil {
+		t.Fatal("expected error for key without pub key")
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		valid    bool
+		checksum bool
+	}{
+		{name: "p2pkh", address: "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", valid: true},
+		{name: "p2sh", address: "37VucYSaXLCAsxYyAPfbSi9eh4iEcbShgf", valid: true},
+		{name: "p2wpkh", address: "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", valid: true},
+		{name: "p2wpkh upper case", address: "BC1QCR8TE4KR609GCAWUTMRZA0J4XV80JY8Z306FYU", valid: true},
+		{name: "p2tr", address: "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", valid: true},
+		{name: "base58 typo", address: "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabB", checksum: true},
+		{name: "bech32 typo", address: "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyv", checksum: true},
+		{name: "mixed case", address: "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyU"},
+		// https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki#test-vectors-for-v0-v16-native-segregated-witness-addresses
+		{name: "v0 with bech32m", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh"},
+		{name: "wrong v0 program length", address: "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P"},
+		{name: "wrong v1 program length", address: "bc1pw5dgrnzv"},
+	}
+
+	for _, test := range tests {
+		err := ValidateAddress(test.address)
+		if test.valid {
+			if err != nil {
+				t.Fatal(test.name, err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Fatal(test.name, "expected error for", test.address)
+		}
+
+		if errors.Is(err, ErrAddressChecksum) != test.checksum {
+			t.Fatal(test.name, "unexpected checksum error", err)
+		}
+	}
+}
+
+func TestKey_Addresses_ParamsOverride(t *testing.T) {
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/84h/0h/0h/0/0",
+			AddrType:       AddrTypeSegWitNative,
+			Bech32HRP:      "custom",
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, typedAddress := range key.Addresses() {
+		if typedAddress.Type == "p2wpkh" {
+			found = typedAddress.Address == key.Addr
+		}
+	}
+
+	if !found {
+		t.Fatal("expected p2wpkh address", key.Addr, "among addresses", key.Addresses())
+	}
+}
+
+func TestKey_P2pkhAddresses(t *testing.T) {
+	// pub keys of private key 1
+	for _, pubKeyHex := range []string{
+		"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	} {
+		key, err := DecodePublicHexWithNetwork(pubKeyHex, NetworkTypeMainnet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		compressed, uncompressed, err := key.P2pkhAddresses()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if compressed != "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH" {
+			t.Fatal("expected 1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH, got", compressed)
+		}
+
+		if uncompressed != "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm" {
+			t.Fatal("expected 1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm, got", uncompressed)
+		}
+	}
+}
+
+func TestAddressesFromPubKeyHash(t *testing.T) {
+	// hash160 of compressed pub key of private key 1
+	hash160, err := hex.DecodeString("751e76e8199196d454941c45d1b3a323f1433bd6")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addresses, err := AddressesFromPubKeyHash(hash160, NetworkTypeMainnet, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		AddrTypeP2pkhOrP2sh: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		AddrTypeP2wpkhP2sh:  "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN",
+		AddrTypeP2wpkh:      "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+	}
+
+	if len(addresses) != len(expected) {
+		t.Fatal("expected", len(expected), "addresses, got", len(addresses))
+	}
+
+	for scriptType, addr := range expected {
+		if addresses[scriptType] != addr {
+			t.Fatal(scriptType, "expected", addr, ", got", addresses[scriptType])
+		}
+	}
+
+	if _, err := AddressesFromPubKeyHash(hash160, NetworkTypeMainnet, []string{AddrTypeTaproot}); err == nil {
+		t.Fatal("expected error for taproot script type")
+	}
+
+	if _, err := AddressesFromPubKeyHash(hash160[1:], NetworkTypeMainnet, nil); err == nil {
+		t.Fatal("expected error for short pub key hash")
+	}
+}
+
+func TestAddressesFromWIF_Uncompressed(t *testing.T) {
+	// uncompressed wif of private key 1
+	const wif = "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf"
+
+	addresses, err := AddressesFromWIF(wif, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(addresses) != 1 || addresses[AddrTypeP2pkhOrP2sh] != "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm" {
+		t.Fatal("expected only p2pkh address 1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm, got", addresses)
+	}
+
+	for _, scriptType := range []string{AddrTypeP2wpkhP2sh, AddrTypeP2wpkh, AddrTypeP2tr, AddrTypeSegWitNative} {
+		if _, err := AddressesFromWIF(wif, []string{AddrTypeLegacy, scriptType}); err == nil {
+			t.Fatal("expected error for", scriptType, "address of uncompressed wif")
+		}
+	}
+}
+
+func TestKey_Addresses_Uncompressed(t *testing.T) {
+	wifKey, err := DecodePrivateWifKey("5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hexKey, err := DecodePublicHexWithNetwork(
+		"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"+
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+		NetworkTypeMainnet,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []*Key{wifKey, hexKey} {
+		addresses := key.Addresses()
+		if len(addresses) != 1 {
+			t.Fatal("expected only p2pkh address for uncompressed key, got", addresses)
+		}
+
+		if addresses[0].Type != "p2pkh" || addresses[0].Address != key.Addr ||
+			key.Addr != "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm" {
+			t.Fatal("expected p2pkh address 1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm, got", addresses[0], key.Addr)
+		}
+	}
+}
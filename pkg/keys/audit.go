@@ -0,0 +1,48 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// auditHashDomain separates audit hashes from other sha256 uses and
+// versions the canonical serialization
+const auditHashDomain = "bip32/audit/v1"
+
+// AuditHash returns hex encoded sha256 over a canonical serialization of
+// non-secret fields of the key, i.e., derivation path, coin type, network,
+// script type, pub key, extended pub key and address. Two derivations yield
+// the same hash only if they produced identical keys, allowing derivations
+// to be logged and compared without persisting key material.
+func (k *Key) AuditHash() string {
+	coinType := k.CoinType
+	if len(coinType) == 0 {
+		coinType = CoinTypeBtc
+	}
+
+	// hardened marker is fixed so that hashes do not depend on
+	// how derivation paths are rendered
+	derivationPath := k.DerivationPath
+	if len(k.ResolvedIndices) > 0 {
+		derivationPath = FormatDerivationPath(k.ResolvedIndices, HardenedMarkerH)
+	}
+
+	h := sha256.New()
+	varint := make([]byte, binary.MaxVarintLen64)
+	for _, field := range []string{
+		auditHashDomain,
+		derivationPath,
+		coinType,
+		normalizeNetwork(k.Network),
+		keyScriptType(k),
+		k.PubKeyHex,
+		k.XPub,
+		k.Addr,
+	} {
+		h.Write(varint[:binary.PutUvarint(varint, uint64(len(field)))])
+		h.Write([]byte(field))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
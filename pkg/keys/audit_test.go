@@ -0,0 +1,41 @@
+package keys
+
+import "testing"
+
+func TestKey_AuditHash(t *testing.T) {
+	config := &Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m/84h/0h/0h/0/0",
+		AddrType:       AddrTypeSegWitNative,
+	}
+
+	key, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(key.AuditHash()) != 64 || key.AuditHash() != other.AuditHash() {
+		t.Fatal("expected identical derivations to have equal audit hashes")
+	}
+
+	other.PrvKeyWif, other.XPrv, other.Seed = "", "", ""
+	if key.AuditHash() != other.AuditHash() {
+		t.Fatal("expected audit hash to ignore secret fields")
+	}
+
+	config.DerivationPath = "m/84h/0h/0h/0/1"
+	other, err = New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.AuditHash() == other.AuditHash() {
+		t.Fatal("expected different derivations to have different audit hashes")
+	}
+}
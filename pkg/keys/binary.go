@@ -0,0 +1,143 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// binaryFormatVersion is the version of binary layout of Key
+const binaryFormatVersion = 1
+
+// flags of binary layout of Key
+const (
+	binaryFlagPrivate byte = 1 << iota
+	binaryFlagCompressed
+	binaryFlagHardened
+)
+
+// binaryStringFields returns pointers to string fields of key in the
+// order of binary layout
+func (k *Key) binaryStringFields() []*string {
+	return []*string{
+		&k.Seed,
+		&k.XPrv,
+		&k.XPub,
+		&k.PubKeyHex,
+		&k.PrvKeyWif,
+		&k.Addr,
+		&k.AddrType,
+		&k.DerivationPath,
+		&k.PrimaryAddress,
+		&k.CoinType,
+		&k.Network,
+		&k.segWitNested,
+		&k.segWitBech32,
+	}
+}
+
+// MarshalBinary encodes key in a compact binary layout consisting of
+// a version byte, a flags byte, length prefixed string fields and
+// resolved indices, all lengths and indices being uvarint encoded
+func (k *Key) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	varint := make([]byte, binary.MaxVarintLen64)
+
+	var flags byte
+	if k.IsPrivate {
+		flags |= binaryFlagPrivate
+	}
+	if k.IsCompressed {
+		flags |= binaryFlagCompressed
+	}
+	if k.IsHardened {
+		flags |= binaryFlagHardened
+	}
+
+	buf.WriteByte(binaryFormatVersion)
+	buf.WriteByte(flags)
+
+	for _, field := range k.binaryStringFields() {
+		buf.Write(varint[:binary.PutUvarint(varint, uint64(len(*field)))])
+		buf.WriteString(*field)
+	}
+
+	buf.Write(varint[:binary.PutUvarint(varint, uint64(len(k.ResolvedIndices)))])
+	for _, index := range k.ResolvedIndices {
+		buf.Write(varint[:binary.PutUvarint(varint, uint64(index))])
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes key encoded via MarshalBinary
+func (k *Key) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+
+	version, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("failed to read binary format version: %w", err)
+	}
+	if version != binaryFormatVersion {
+		return fmt.Errorf("unsupported binary format version %d", version)
+	}
+
+	flags, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("failed to read flags: %w", err)
+	}
+
+	var key Key
+	key.IsPrivate = flags&binaryFlagPrivate != 0
+	key.IsCompressed = flags&binaryFlagCompressed != 0
+	key.IsHardened = flags&binaryFlagHardened != 0
+
+	for i, field := range key.binaryStringFields() {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return fmt.Errorf("failed to read length of field %d: %w", i, err)
+		}
+
+		if n > uint64(len(data)) {
+			return fmt.Errorf("invalid length %d of field %d", n, i)
+		}
+
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return fmt.Errorf("failed to read field %d: %w", i, err)
+		}
+		*field = string(b)
+	}
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fmt.Errorf("failed to read number of resolved indices: %w", err)
+	}
+
+	if n > uint64(len(data)) {
+		return fmt.Errorf("invalid number of resolved indices %d", n)
+	}
+
+	if n > 0 {
+		key.ResolvedIndices = make([]uint32, n)
+		for i := range key.ResolvedIndices {
+			index, err := binary.ReadUvarint(r)
+			if err != nil {
+				return fmt.Errorf("failed to read resolved index %d: %w", i, err)
+			}
+			if index > 1<<32-1 {
+				return fmt.Errorf("invalid resolved index %d", index)
+			}
+			key.ResolvedIndices[i] = uint32(index)
+		}
+	}
+
+	if r.Len() > 0 {
+		return fmt.Errorf("unexpected trailing data")
+	}
+
+	*k = key
+
+	return nil
+}
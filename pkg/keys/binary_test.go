@@ -0,0 +1,55 @@
+package keys
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKey_MarshalBinary(t *testing.T) {
+	key := &Key{
+		Seed:            "seed",
+		XPrv:            "xprv",
+		XPub:            "xpub",
+		PubKeyHex:       "pubkeyhex",
+		PrvKeyWif:       "wif",
+		Addr:            "addr",
+		AddrType:        AddrTypeP2wpkh,
+		DerivationPath:  "m/84h/0h/0h/0/0",
+		PrimaryAddress:  "primary",
+		CoinType:        CoinTypeBtc,
+		Network:         NetworkTypeMainnet,
+		IsPrivate:       true,
+		IsCompressed:    true,
+		IsHardened:      true,
+		ResolvedIndices: []uint32{84 | 1<<31, 1 << 31, 1 << 31, 0, 1<<32 - 1},
+		segWitNested:    "nested",
+		segWitBech32:    "bech32",
+	}
+
+	data, err := key.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &Key{}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(key, decoded) {
+		t.Fatalf("expected %+v, got %+v", key, decoded)
+	}
+
+	// truncated input
+	for i := range data {
+		if err := (&Key{}).UnmarshalBinary(data[:i]); err == nil {
+			t.Fatal("expected error for input truncated at", i)
+		}
+	}
+
+	// unknown version
+	unknown := append([]byte{binaryFormatVersion + 1}, data[1:]...)
+	if err := (&Key{}).UnmarshalBinary(unknown); err == nil {
+		t.Fatal("expected error for unknown binary format version")
+	}
+}
@@ -0,0 +1,102 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"fmt"
+
+	"github.com/tyler-smith/go-bip32"
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// BIP-85 deterministic entropy from BIP-32 keychains
+// https://github.com/bitcoin/bips/blob/master/bip-0085.mediawiki
+const (
+	// BIP85Purpose is the purpose level of BIP-85 derivation paths
+	BIP85Purpose = 83696968
+	// BIP85AppBIP39 is the application number of BIP-39 mnemonics
+	BIP85AppBIP39 = 39
+	// bip85LanguageEnglish is the language level of english BIP-39 mnemonics
+	bip85LanguageEnglish = 0
+)
+
+// bip85HmacKey is the hmac key applied to derived private keys
+const bip85HmacKey = "bip-entropy-from-k"
+
+// BIP85Entropy derives length bytes of deterministic entropy, at most 64,
+// from the master key of the seed at derivation path m/83696968h/apph/indexh
+// using the generic HMAC-SHA512 entropy application of BIP-85. Entropy of
+// distinct app and index pairs is independent, allowing one master seed to
+// back many deterministic child wallets.
+func BIP85Entropy(masterSeed []byte, app, index uint32, length int) ([]byte, error) {
+	masterKey, err := bip32.NewMasterKey(masterSeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate root key: %w", err)
+	}
+
+	return bip85Entropy(masterKey, []uint32{app, index}, length)
+}
+
+// BIP85Mnemonic derives a deterministic english BIP-39 child mnemonic of
+// 12, 18 or 24 words from the master key of the seed at derivation path
+// m/83696968h/39h/0h/wordsh/indexh using the BIP-39 application of BIP-85
+func BIP85Mnemonic(masterSeed []byte, words int, index uint32) (string, error) {
+	masterKey, err := bip32.NewMasterKey(masterSeed)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate root key: %w", err)
+	}
+
+	return bip85Mnemonic(masterKey, words, index)
+}
+
+// bip85Mnemonic derives english BIP-39 child mnemonic from a master key
+func bip85Mnemonic(masterKey *bip32.Key, words int, index uint32) (string, error) {
+	var length int
+	switch words {
+	case 12, 18, 24:
+		length = words * 4 / 3
+	default:
+		return "", fmt.Errorf("%w %d, must be 12, 18 or 24", ErrInvalidMnemonicWordCount, words)
+	}
+
+	entropy, err := bip85Entropy(
+		masterKey,
+		[]uint32{BIP85AppBIP39, bip85LanguageEnglish, uint32(words), index},
+		length,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return entropyToMnemonic(entropy, wordlists.English), nil
+}
+
+// bip85Entropy derives entropy at hardened levels of BIP-85 purpose
+// followed by hardened indices, which must be unhardened values
+func bip85Entropy(masterKey *bip32.Key, indices []uint32, length int) ([]byte, error) {
+	if length <= 0 || length > sha512.Size {
+		return nil, fmt.Errorf("invalid entropy length %d, must be in 1:%d", length, sha512.Size)
+	}
+
+	if !masterKey.IsPrivate {
+		return nil, fmt.Errorf("bip85 entropy requires a private master key")
+	}
+
+	hardenedIndices := []uint32{bip32.FirstHardenedChild + BIP85Purpose}
+	for _, index := range indices {
+		if index >= bip32.FirstHardenedChild {
+			return nil, fmt.Errorf("invalid index %d, must be less than %d", index, bip32.FirstHardenedChild)
+		}
+		hardenedIndices = append(hardenedIndices, bip32.FirstHardenedChild+index)
+	}
+
+	xKey, err := extendedKeyToIndexDerivedExtendedKey(masterKey, hardenedIndices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive extended key: %w", err)
+	}
+
+	mac := hmac.New(sha512.New, []byte(bip85HmacKey))
+	mac.Write(xKey.Key)
+
+	return mac.Sum(nil)[:length], nil
+}
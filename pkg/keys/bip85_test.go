@@ -0,0 +1,72 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// test vectors of BIP-85
+// https://github.com/bitcoin/bips/blob/master/bip-0085.mediawiki#test-vectors
+const testBIP85MasterKey = "xprv9s21ZrQH143K2LBWUUQRFXhucrQqBpKdRRxNVq2zBqsx8HVqFk2uYo8kmbaLLHRdqtQpUm98uKfu3vca1LqdGhUtyoFnCNkfmXRyPXLjbKb"
+
+func TestBIP85Entropy(t *testing.T) {
+	tests := []struct {
+		app      uint32
+		index    uint32
+		expected string
+	}{
+		{0, 0, "efecfbccffea313214232d29e71563d941229afb4338c21f9517c41aaa0d16f00b83d2a09ef747e7a64e8e2bd5a14869e693da66ce94ac2da570ab7ee48618f7"},
+		{0, 1, "70c6e3e8ebee8dc4c0dbba66076819bb8c09672527c4277ca8729532ad711872218f826919f6b67218adde99018a6df9095ab2b58d803b5b93ec9802085a690e"},
+	}
+
+	masterKey, err := deserializeExtendedKey(testBIP85MasterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		entropy, err := bip85Entropy(masterKey, []uint32{test.app, test.index}, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if hex.EncodeToString(entropy) != test.expected {
+			t.Fatal("expected", test.expected, ", got", hex.EncodeToString(entropy))
+		}
+	}
+}
+
+func TestBIP85Mnemonic(t *testing.T) {
+	tests := []struct {
+		words    int
+		expected string
+	}{
+		{12, "girl mad pet galaxy egg matter matrix prison refuse sense ordinary nose"},
+		{18, "near account window bike charge season chef number sketch tomorrow excuse sniff circle vital hockey outdoor supply token"},
+		{24, "puppy ocean match cereal symbol another shed magic wrap hammer bulb intact gadget divorce twin tonight reason outdoor destroy simple truth cigar social volcano"},
+	}
+
+	masterKey, err := deserializeExtendedKey(testBIP85MasterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		mnemonic, err := bip85Mnemonic(masterKey, test.words, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if mnemonic != test.expected {
+			t.Fatal("expected", test.expected, ", got", mnemonic)
+		}
+
+		if err := ValidateMnemonic(mnemonic); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := bip85Mnemonic(masterKey, 15, 0); err == nil {
+		t.Fatal("expected error for unsupported word count")
+	}
+}
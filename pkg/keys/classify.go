@@ -0,0 +1,89 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/tyler-smith/go-bip32"
+)
+
+// ClassifyInput returns the type of input, such as mnemonic, wif, xprv, xpub,
+// address or hex-pubkey, without fully decoding it. Mnemonics are detected by
+// word count and membership of words in BIP-39 english wordlist, however,
+// mnemonic checksum is not verified.
+func ClassifyInput(s string) (string, error) {
+	if err := checkInputLength(s); err != nil {
+		return "", err
+	}
+
+	s = tidyInput(s)
+	if len(s) == 0 {
+		return "", fmt.Errorf("input is empty")
+	}
+
+	if fields := strings.Fields(s); len(fields) > 1 {
+		switch len(fields) {
+		case 12, 15, 18, 21, 24:
+		default:
+			return "", fmt.Errorf("invalid mnemonic word count %d, must be 12, 15, 18, 21 or 24", len(fields))
+		}
+
+		for _, field := range fields {
+			if _, ok := englishWordIndices[strings.ToLower(field)]; !ok {
+				return "", fmt.Errorf("input is not a mnemonic, unknown word %q", field)
+			}
+		}
+
+		return InputTypeMnemonic, nil
+	}
+
+	if IsValidBase58String(s) {
+		switch len(base58.Decode(s)) {
+		case 82:
+			if bip32Key, err := bip32.B58Deserialize(s); err == nil {
+				if _, ok := lookupVersions(bip32Key.Version); ok {
+					if bip32Key.IsPrivate {
+						return InputTypeXprv, nil
+					}
+					return InputTypeXpub, nil
+				}
+			}
+		case 37, 38:
+			if _, err := btcutil.DecodeWIF(s); err == nil {
+				return InputTypeWif, nil
+			}
+		}
+	}
+
+	// hex encoded pub keys are checked before addresses, since
+	// btcutil decodes them as pay-to-pubkey addresses
+	if b, err := hex.DecodeString(s); err == nil {
+		if _, err := btcec.ParsePubKey(b, btcec.S256()); err == nil {
+			return InputTypeHexPubKey, nil
+		}
+	}
+
+	if _, ok := zecAddrNetwork(s); ok {
+		return InputTypeAddress, nil
+	}
+
+	// segwit addresses are validated locally, since btcutil
+	// cannot decode witness v1 addresses
+	if hrp, ok := segWitHRP(s); ok {
+		if err := validateSegWitAddress(s, hrp); err == nil {
+			return InputTypeAddress, nil
+		}
+	}
+
+	for _, addrNetwork := range addrNetworks {
+		if _, err := btcutil.DecodeAddress(s, addrNetwork.params); err == nil {
+			return InputTypeAddress, nil
+		}
+	}
+
+	return "", fmt.Errorf("input is not a valid mnemonic, wif, extended key, address or hex encoded pub key")
+}
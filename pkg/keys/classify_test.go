@@ -0,0 +1,52 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/tyler-smith/go-bip32"
+)
+
+func TestClassifyInput(t *testing.T) {
+	master, err := bip32.NewMasterKey(TestSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{master.B58Serialize(), InputTypeXprv},
+		{master.PublicKey().B58Serialize(), InputTypeXpub},
+		{testZpubAbandonAbout, InputTypeXpub},
+		{"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", InputTypeWif},
+		{"5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf", InputTypeWif},
+		{"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", InputTypeHexPubKey},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", InputTypeAddress},
+		{"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", InputTypeAddress},
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", InputTypeAddress},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", InputTypeMnemonic},
+	}
+
+	for _, test := range tests {
+		inputType, err := ClassifyInput(test.input)
+		if err != nil {
+			t.Fatal(test.input, err)
+		}
+
+		if inputType != test.expected {
+			t.Fatal(test.input, "expected", test.expected, ", got", inputType)
+		}
+	}
+
+	for _, input := range []string{
+		"",
+		"abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon bitcoin2",
+		"not a key",
+	} {
+		if _, err := ClassifyInput(input); err == nil {
+			t.Fatal("expected error for", input)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Coin describes chain params and extended key versions of a coin
+// that shares bitcoin key derivation and address encoding
+type Coin struct {
+	// Params are chain params keyed by network, params are required
+	// for both mainnet and testnet
+	Params map[string]*chaincfg.Params
+	// KeyVersions are extended key versions keyed by network, addr type
+	// and key type, such as mainnet/p2pkh-or-p2sh/pub
+	KeyVersions map[string][]byte
+	// SegWit is true when the coin supports segwit addresses
+	SegWit bool
+}
+
+// coinRegistry holds coins registered in addition to btc and zec, keyed
+// by coin type. Coins are added via RegisterCoin, which also registers
+// their key versions.
+var coinRegistry = map[string]*Coin{}
+
+// registryMu guards coinRegistry and the package level key version maps,
+// which are extended by RegisterCoin
+var registryMu sync.RWMutex
+
+// versionToCoinType maps key versions unique to a registered coin
+// to the coin type, allowing coin detection when deriving keys
+var versionToCoinType = map[string]string{}
+
+// key versions of registered coins
+// https://github.com/trezor/trezor-firmware/tree/master/common/defs/bitcoin
+const (
+	ltub = "019da462"
+	ltpv = "019d9cfe"
+	mtub = "01b26ef6"
+	mtpv = "01b26792"
+	dgub = "02facafd"
+	dgpv = "02fac398"
+	drkp = "02fe52cc"
+	drkv = "02fe52f8"
+)
+
+// registerBuiltinCoins registers coins shipped with the package and
+// is called once package level key versions are initialized
+func registerBuiltinCoins() {
+	coins := map[string]*Coin{
+		CoinTypeLtc: {
+			Params: map[string]*chaincfg.Params{
+				NetworkTypeMainnet: newCoinParams(&chaincfg.MainNetParams, "litecoin", 0x30, 0x32, 0xb0, "ltc", 2),
+				NetworkTypeTestnet: newCoinParams(&chaincfg.TestNet3Params, "litecoin-testnet", 0x6f, 0x3a, 0xef, "tltc", 1),
+			},
+			KeyVersions: map[string][]byte{
+				path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(ltub),
+				path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(ltpv),
+				path.Join(NetworkTypeMainnet, AddrTypeP2wpkhP2sh, KeyTypePub):  mustDecodeHex(mtub),
+				path.Join(NetworkTypeMainnet, AddrTypeP2wpkhP2sh, KeyTypePrv):  mustDecodeHex(mtpv),
+				path.Join(NetworkTypeMainnet, AddrTypeP2wpkh, KeyTypePub):      mustDecodeHex(zpub),
+				path.Join(NetworkTypeMainnet, AddrTypeP2wpkh, KeyTypePrv):      mustDecodeHex(zprv),
+				path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(tpub),
+				path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(tprv),
+				path.Join(NetworkTypeTestnet, AddrTypeP2wpkhP2sh, KeyTypePub):  mustDecodeHex(upub),
+				path.Join(NetworkTypeTestnet, AddrTypeP2wpkhP2sh, KeyTypePrv):  mustDecodeHex(uprv),
+				path.Join(NetworkTypeTestnet, AddrTypeP2wpkh, KeyTypePub):      mustDecodeHex(vpub),
+				path.Join(NetworkTypeTestnet, AddrTypeP2wpkh, KeyTypePrv):      mustDecodeHex(vprv),
+			},
+			SegWit: true,
+		},
+		CoinTypeDoge: {
+			Params: map[string]*chaincfg.Params{
+				NetworkTypeMainnet: newCoinParams(&chaincfg.MainNetParams, "dogecoin", 0x1e, 0x16, 0x9e, "", 3),
+				NetworkTypeTestnet: newCoinParams(&chaincfg.TestNet3Params, "dogecoin-testnet", 0x71, 0xc4, 0xf1, "", 1),
+			},
+			KeyVersions: map[string][]byte{
+				path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(dgub),
+				path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(dgpv),
+				path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(tpub),
+				path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(tprv),
+			},
+		},
+		CoinTypeDash: {
+			Params: map[string]*chaincfg.Params{
+				NetworkTypeMainnet: newCoinParams(&chaincfg.MainNetParams, "dash", 0x4c, 0x10, 0xcc, "", 5),
+				NetworkTypeTestnet: newCoinParams(&chaincfg.TestNet3Params, "dash-testnet", 0x8c, 0x13, 0xef, "", 1),
+			},
+			KeyVersions: map[string][]byte{
+				path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(drkp),
+				path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(drkv),
+				path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(tpub),
+				path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(tprv),
+			},
+		},
+	}
+
+	for coinType, coin := range coins {
+		if err := RegisterCoin(coinType, coin); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// newCoinParams returns a copy of base params with address ids,
+// wif private key id, bech32 hrp and SLIP-44 coin type replaced
+func newCoinParams(base *chaincfg.Params, name string,
+	pubKeyHashAddrID, scriptHashAddrID, privateKeyID byte,
+	bech32HRP string, hdCoinType uint32) *chaincfg.Params {
+	p := *base
+	p.Name = name
+	p.PubKeyHashAddrID = pubKeyHashAddrID
+	p.ScriptHashAddrID = scriptHashAddrID
+	p.PrivateKeyID = privateKeyID
+	p.Bech32HRPSegwit = bech32HRP
+	p.HDCoinType = hdCoinType
+	return &p
+}
+
+// RegisterCoin adds a copy of a coin to the coin registry and registers its
+// key versions. Key versions not used by any other registered coin are also
+// registered for detection of the coin when deriving keys.
+func RegisterCoin(coinType string, coin *Coin) error {
+	coinType = strings.ToLower(coinType)
+	if len(coinType) == 0 {
+		return fmt.Errorf("coin type cannot be empty")
+	}
+
+	if coinType == CoinTypeBtc || coinType == CoinTypeZec {
+		return fmt.Errorf("coin type %s is built-in and cannot be registered", coinType)
+	}
+
+	if coin == nil {
+		return fmt.Errorf("coin cannot be nil")
+	}
+
+	coin = coin.clone()
+
+	for _, network := range []string{NetworkTypeMainnet, NetworkTypeTestnet} {
+		if coin.Params[network] == nil {
+			return fmt.Errorf("%w for coin type %s on %s", ErrMissingNetworkParams, coinType, network)
+		}
+	}
+
+	for k, version := range coin.KeyVersions {
+		if len(version) != 4 {
+			return fmt.Errorf("key version %s must be 4 bytes long, found %d bytes", k, len(version))
+		}
+
+		if _, ok := coin.KeyVersions[path.Join(path.Dir(k), KeyTypePub)]; !ok {
+			return fmt.Errorf("missing pub key version for %s", path.Dir(k))
+		}
+
+		if _, ok := coin.KeyVersions[path.Join(path.Dir(k), KeyTypePrv)]; !ok {
+			return fmt.Errorf("missing prv key version for %s", path.Dir(k))
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := coinRegistry[coinType]; ok {
+		return fmt.Errorf("coin type %s is already registered", coinType)
+	}
+
+	for k, version := range coin.KeyVersions {
+		keyVersions[path.Join(coinType, k)] = version
+
+		v := hex.EncodeToString(version)
+		if _, ok := versionToVersions[v]; ok {
+			continue
+		}
+
+		network, addrType := path.Dir(path.Dir(k)), path.Base(path.Dir(k))
+		switch network {
+		case NetworkTypeMainnet:
+			mainnetVersions[v] = struct{}{}
+		case NetworkTypeTestnet:
+			testnetVersions[v] = struct{}{}
+		default:
+			continue
+		}
+
+		versionToVersions[v] = []string{
+			hex.EncodeToString(coin.KeyVersions[path.Join(path.Dir(k), KeyTypePub)]),
+			hex.EncodeToString(coin.KeyVersions[path.Join(path.Dir(k), KeyTypePrv)]),
+		}
+		versionToAddrType[v] = addrType
+		versionToCoinType[v] = coinType
+	}
+
+	segWitCoinTypes[coinType] = coin.SegWit
+	coinRegistry[coinType] = coin
+	sortKeyVersions()
+
+	return nil
+}
+
+// LookupCoin returns a copy of a registered coin, modifying the
+// returned coin has no effect on the coin registry
+func LookupCoin(coinType string) (*Coin, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	coin, ok := coinRegistry[strings.ToLower(coinType)]
+	if !ok {
+		return nil, false
+	}
+
+	return coin.clone(), true
+}
+
+// clone returns a copy of the coin with its maps copied
+func (c *Coin) clone() *Coin {
+	out := &Coin{
+		Params:      make(map[string]*chaincfg.Params, len(c.Params)),
+		KeyVersions: make(map[string][]byte, len(c.KeyVersions)),
+		SegWit:      c.SegWit,
+	}
+
+	for network, params := range c.Params {
+		out.Params[network] = params
+	}
+
+	for k, version := range c.KeyVersions {
+		out.KeyVersions[k] = append([]byte(nil), version...)
+	}
+
+	return out
+}
+
+// registeredParams returns chain params of all registered coins
+// keyed by network
+func registeredParams() map[string][]*chaincfg.Params {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	params := make(map[string][]*chaincfg.Params)
+	for _, coin := range coinRegistry {
+		for network, p := range coin.Params {
+			params[network] = append(params[network], p)
+		}
+	}
+
+	return params
+}
+
+// networkParams returns chain params of a coin type and network,
+// or nil if none are registered
+func networkParams(coinType, network string) *chaincfg.Params {
+	if coin, ok := LookupCoin(coinType); ok {
+		return coin.Params[network]
+	}
+
+	return netParams[network]
+}
+
+// coinTypes returns sorted list of supported coin types
+func coinTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return sortedCoinTypes()
+}
+
+// sortedCoinTypes returns sorted list of supported coin types
+// and expects registryMu to be held by the caller
+func sortedCoinTypes() []string {
+	coinTypes := []string{CoinTypeBtc, CoinTypeZec}
+	for coinType := range coinRegistry {
+		coinTypes = append(coinTypes, coinType)
+	}
+	sort.Strings(coinTypes[2:])
+
+	return coinTypes
+}
+
+// lookupKeyVersion returns key version registered for a coin type,
+// network, addr type and key type
+func lookupKeyVersion(coinType, network, addrType, keyType string) ([]byte, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	version, ok := keyVersions[path.Join(coinType, network, addrType, keyType)]
+	return version, ok
+}
+
+// lookupVersions returns pub and prv key versions, hex encoded, for
+// a key version
+func lookupVersions(version []byte) ([]string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	versions, ok := versionToVersions[hex.EncodeToString(version)]
+	return versions, ok
+}
+
+// versionNetwork returns network of a key version
+func versionNetwork(version []byte) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if _, ok := mainnetVersions[hex.EncodeToString(version)]; ok {
+		return NetworkTypeMainnet, true
+	}
+
+	if _, ok := testnetVersions[hex.EncodeToString(version)]; ok {
+		return NetworkTypeTestnet, true
+	}
+
+	return "", false
+}
+
+// versionAddrType returns addr type of a key version
+func versionAddrType(version []byte) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return versionToAddrType[hex.EncodeToString(version)]
+}
+
+// versionCoinType returns coin type of a key version unique
+// to a registered coin
+func versionCoinType(version []byte) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	coinType, ok := versionToCoinType[hex.EncodeToString(version)]
+	return coinType, ok
+}
+
+// keyVersionEntries returns ordered key version entries, the returned
+// slice is replaced, not modified, when coins are registered
+func keyVersionEntries() []keyVersionEntry {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return orderedKeyVersions
+}
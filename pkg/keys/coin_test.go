@@ -0,0 +1,181 @@
+package keys
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestNew_CoinRegistry(t *testing.T) {
+	seed := TestSeed()
+
+	tests := []struct {
+		coinType       string
+		addrType       string
+		derivationPath string
+		addr           string
+	}{
+		{CoinTypeLtc, AddrTypeLegacy, "m/44h/2h/0h/0/0", "LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez"},
+		{CoinTypeLtc, AddrTypeSegWitCompatible, "m/49h/2h/0h/0/0", "M7wtsL7wSHDBJVMWWhtQfTMSYYkyooAAXM"},
+		{CoinTypeLtc, AddrTypeSegWitNative, "m/84h/2h/0h/0/0", "ltc1qjmxnz78nmc8nq77wuxh25n2es7rzm5c2rkk4wh"},
+		{CoinTypeDoge, AddrTypeLegacy, "m/44h/3h/0h/0/0", "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC"},
+		{CoinTypeDash, AddrTypeLegacy, "m/44h/5h/0h/0/0", "XoJA8qE3N2Y3jMLEtZ3vcN42qseZ8LvFf5"},
+	}
+
+	for _, test := range tests {
+		key, err := New(
+			&Config{
+				Seed:           seed,
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "auto",
+				AddrType:       test.addrType,
+				CoinType:       test.coinType,
+			},
+		)
+		if err != nil {
+			t.Fatal(test.coinType, test.addrType, err)
+		}
+
+		if key.DerivationPath != test.derivationPath {
+			t.Fatal("expected", test.derivationPath, ", got", key.DerivationPath)
+		}
+
+		if key.Addr != test.addr {
+			t.Fatal("expected", test.addr, ", got", key.Addr)
+		}
+
+		// legacy key versions are unique to each coin
+		if test.addrType != AddrTypeLegacy {
+			continue
+		}
+
+		derivedKey, err := Derive(key.XPrv, "m")
+		if err != nil {
+			t.Fatal(test.coinType, err)
+		}
+
+		if derivedKey.CoinType != test.coinType || derivedKey.Addr != test.addr {
+			t.Fatal("expected", test.coinType, test.addr, ", got", derivedKey.CoinType, derivedKey.Addr)
+		}
+	}
+}
+
+func TestConfig_Validate_MissingNetworkParams(t *testing.T) {
+	const coinType = "missingparams"
+
+	coin := &Coin{
+		Params: map[string]*chaincfg.Params{
+			NetworkTypeMainnet: &chaincfg.MainNetParams,
+			NetworkTypeTestnet: &chaincfg.TestNet3Params,
+		},
+		KeyVersions: map[string][]byte{
+			path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(xpub),
+			path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(xprv),
+			path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(tpub),
+			path.Join(NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(tprv),
+		},
+	}
+
+	if err := RegisterCoin(coinType, coin); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+
+		for k := range coin.KeyVersions {
+			delete(keyVersions, path.Join(coinType, k))
+		}
+		delete(segWitCoinTypes, coinType)
+		delete(coinRegistry, coinType)
+		sortKeyVersions()
+	}()
+
+	// registry holds a copy of the coin, so params are removed
+	// from the registered copy directly
+	registryMu.Lock()
+	delete(coinRegistry[coinType].Params, NetworkTypeTestnet)
+	registryMu.Unlock()
+
+	config := &Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeTestnet,
+		DerivationPath: "m/44h/1h/0h/0/0",
+		AddrType:       AddrTypeLegacy,
+		CoinType:       coinType,
+	}
+
+	if err := config.Validate(); !errors.Is(err, ErrMissingNetworkParams) {
+		t.Fatal("expected ErrMissingNetworkParams, got", err)
+	}
+
+	if _, err := New(config); !errors.Is(err, ErrMissingNetworkParams) {
+		t.Fatal("expected ErrMissingNetworkParams, got", err)
+	}
+}
+
+func TestLookupCoin_ReturnsCopy(t *testing.T) {
+	coin, ok := LookupCoin(CoinTypeLtc)
+	if !ok {
+		t.Fatal("ltc is not registered")
+	}
+
+	delete(coin.Params, NetworkTypeMainnet)
+	coin.KeyVersions[path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePub)][0] ^= 0xff
+
+	coin, ok = LookupCoin(CoinTypeLtc)
+	if !ok {
+		t.Fatal("ltc is not registered")
+	}
+
+	if coin.Params[NetworkTypeMainnet] == nil {
+		t.Fatal("expected registered ltc mainnet params to be unaffected")
+	}
+
+	if !bytes.Equal(coin.KeyVersions[path.Join(NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePub)], mustDecodeHex(ltub)) {
+		t.Fatal("expected registered ltc key versions to be unaffected")
+	}
+}
+
+func TestRegisterCoin_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		coinType := fmt.Sprintf("concurrent%d", i)
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			coin := &Coin{
+				Params: map[string]*chaincfg.Params{
+					NetworkTypeMainnet: &chaincfg.MainNetParams,
+					NetworkTypeTestnet: &chaincfg.TestNet3Params,
+				},
+			}
+			if err := RegisterCoin(coinType, coin); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			if _, err := New(&Config{Seed: TestSeed(), Network: NetworkTypeMainnet, DerivationPath: "auto", AddrType: AddrTypeLegacy, CoinType: CoinTypeLtc}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i := 0; i < 4; i++ {
+		delete(segWitCoinTypes, fmt.Sprintf("concurrent%d", i))
+		delete(coinRegistry, fmt.Sprintf("concurrent%d", i))
+	}
+	sortKeyVersions()
+}
@@ -0,0 +1,13 @@
+package keys
+
+import (
+	"crypto/subtle"
+)
+
+// SecureCompare compares secret material, such as seeds in hex or wif
+// formatted private keys, in constant time. Inputs are compared as
+// strings after trimming surrounding whitespace, no decoding is performed,
+// therefore, encodings of both inputs must match.
+func SecureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(tidyInput(a)), []byte(tidyInput(b))) == 1
+}
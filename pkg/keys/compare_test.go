@@ -0,0 +1,26 @@
+package keys
+
+import (
+	"testing"
+)
+
+func TestSecureCompare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{testSeed, testSeed, true},
+		{" " + testSeed + "\n", testSeed, true},
+		{testSeed, testSeed[:len(testSeed)-1] + "5", false},
+		{testSeed, testSeed[:len(testSeed)-2], false},
+		// hex 00 and base58 1 decode to the same byte
+		{"00", "1", false},
+		{"", "", true},
+	}
+
+	for _, test := range tests {
+		if SecureCompare(test.a, test.b) != test.expected {
+			t.Fatal("expected", test.expected, "comparing", test.a, "and", test.b)
+		}
+	}
+}
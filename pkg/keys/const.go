@@ -1,17 +1,22 @@
 package keys
 
 const (
-	BigN = "/////////////////////rqu3OavSKA7v9JejNA2QUE="
-	BigZ = "0"
+	NetworkTypeMainnet = "mainnet"
+	NetworkTypeTestnet = "testnet"
+	NetworkTypeRegtest = "regtest"
 )
 
 const (
-	NetworkTypeMainnet = "mainnet"
-	NetworkTypeTestnet = "testnet"
+	CoinTypeBtc  = "btc"
+	CoinTypeZec  = "zec"
+	CoinTypeLtc  = "ltc"
+	CoinTypeDoge = "doge"
+	CoinTypeDash = "dash"
 )
 
 const (
-	CoinTypeBtc = "btc"
+	HardenedMarkerH          = "h"
+	HardenedMarkerApostrophe = "'"
 )
 
 const (
@@ -24,6 +29,19 @@ const (
 	KeyFormatHex = "hex"
 )
 
+const (
+	utf8BOM = "\ufeff"
+)
+
 const (
 	base58CharSet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
 )
+
+const (
+	InputTypeMnemonic  = "mnemonic"
+	InputTypeWif       = "wif"
+	InputTypeXprv      = "xprv"
+	InputTypeXpub      = "xpub"
+	InputTypeAddress   = "address"
+	InputTypeHexPubKey = "hex-pubkey"
+)
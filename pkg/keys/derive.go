@@ -0,0 +1,657 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/tyler-smith/go-bip32"
+)
+
+// MaxRangeCount is the default maximum number of child keys DeriveRange
+// derives in one call, guarding against accidental huge allocations.
+// It can be changed per call via WithMaxRangeCount option.
+const MaxRangeCount uint32 = 1 << 20
+
+// SkipFunc is called with branch and index of each child index that is
+// skipped for yielding an invalid child key along with the cause
+type SkipFunc func(branch, index uint32, err error)
+
+// DeriveOption configures derivation via Derive, DeriveIndices,
+// DeriveRelative, DeriveRange, ScanAccounts and DeriveUntilGap
+type DeriveOption func(opts *deriveOptions)
+
+// deriveOptions are resolved options of derivation
+type deriveOptions struct {
+	onSkip        SkipFunc
+	maxRangeCount uint32
+	coinType      string
+}
+
+// WithCoinType sets the coin type of the extended key, such as zec or ltc,
+// for key versions shared with btc, which are otherwise resolved as btc.
+// Key versions unique to a registered coin identify the coin, and deriving
+// them with a different coin type fails.
+func WithCoinType(coinType string) DeriveOption {
+	return func(opts *deriveOptions) {
+		opts.coinType = coinType
+	}
+}
+
+// WithSkipFunc sets a callback that is notified of skipped child indices
+func WithSkipFunc(onSkip SkipFunc) DeriveOption {
+	return func(opts *deriveOptions) {
+		opts.onSkip = onSkip
+	}
+}
+
+// WithMaxRangeCount overrides MaxRangeCount as the maximum number of
+// child keys DeriveRange derives in one call
+func WithMaxRangeCount(maxRangeCount uint32) DeriveOption {
+	return func(opts *deriveOptions) {
+		opts.maxRangeCount = maxRangeCount
+	}
+}
+
+// newDeriveOptions applies derive options over defaults
+func newDeriveOptions(opts []DeriveOption) *deriveOptions {
+	options := &deriveOptions{maxRangeCount: MaxRangeCount}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// skip notifies skip callback, if any, of a skipped child index
+func (o *deriveOptions) skip(branch, index uint32, err error) {
+	if o.onSkip != nil {
+		o.onSkip(branch, index, err)
+	}
+}
+
+// DeriveRange derives count child keys of the branch of the input extended key,
+// i.e., keys at relative derivation paths m/branch/start onwards. Per BIP-32,
+// an index that yields an invalid child key is skipped, and derivation
+// proceeds with the next index. Derivation path of each returned key
+// reflects the index actually used and skipped indices are reported via
+// WithSkipFunc option. Count must not exceed MaxRangeCount, or the limit
+// set via WithMaxRangeCount option, and the range, including any skipped
+// indices, must stay within the non-hardened or hardened index space it
+// starts in, otherwise ErrRangeTooLarge is returned.
+func DeriveRange(keyString string, branch, start, count uint32, opts ...DeriveOption) ([]*Key, error) {
+	options := newDeriveOptions(opts)
+
+	if count > options.maxRangeCount {
+		return nil, fmt.Errorf("invalid count %d exceeding %d: %w", count, options.maxRangeCount, ErrRangeTooLarge)
+	}
+
+	bip32Key, err := deserializeExtendedKey(keyString)
+	if err != nil {
+		return nil, err
+	}
+
+	// fail early instead of deriving a partial range
+	if !bip32Key.IsPrivate {
+		if branch >= bip32.FirstHardenedChild {
+			return nil, fmt.Errorf("invalid branch %d: %w", branch, ErrHardenedFromPublic)
+		}
+
+		if uint64(start)+uint64(count) > uint64(bip32.FirstHardenedChild) {
+			return nil, fmt.Errorf("invalid index range starting at %d with count %d: %w",
+				start, count, ErrHardenedFromPublic)
+		}
+	}
+
+	end := uint64(bip32.FirstHardenedChild)
+	if start >= bip32.FirstHardenedChild {
+		end = 1 << 32
+	}
+
+	if uint64(start)+uint64(count) > end {
+		return nil, fmt.Errorf("invalid index range starting at %d with count %d: %w",
+			start, count, ErrRangeTooLarge)
+	}
+
+	branchKey, err := extendedKeyToIndexDerivedExtendedKey(bip32Key, []uint32{branch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive branch %d: %w", branch, err)
+	}
+
+	keys := make([]*Key, 0, count)
+	for next := uint64(start); uint32(len(keys)) < count; next++ {
+		// skipped indices shift the range, which must still end
+		// within its index space
+		if next >= end {
+			return nil, fmt.Errorf("invalid index range starting at %d with count %d, skipped indices run past %d: %w",
+				start, count, end-1, ErrRangeTooLarge)
+		}
+
+		index := uint32(next)
+		childKey, err := extendedKeyToIndexDerivedExtendedKey(branchKey, []uint32{index})
+		if err != nil {
+			if errors.Is(err, ErrInvalidChildKey) {
+				options.skip(branch, index, err)
+				continue
+			}
+			return nil, fmt.Errorf("failed to derive index %d of branch %d: %w", index, branch, err)
+		}
+
+		key, err := derivedExtendedKeyToKey(childKey, options.coinType)
+		if err != nil {
+			return nil, err
+		}
+
+		key.ResolvedIndices = []uint32{branch, index}
+		key.DerivationPath = formatDerivationPath(key.ResolvedIndices)
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// DeriveRelative derives key at a derivation path relative to the input
+// extended key, which is treated as the current node regardless of its depth.
+// Relative paths such as 0/5 are accepted without m prefix.
+func DeriveRelative(keyString, relPath string, opts ...DeriveOption) (*Key, error) {
+	relPath = strings.Trim(strings.TrimSpace(relPath), "/")
+	if len(relPath) > 0 && strings.Split(relPath, "/")[0] != "m" {
+		relPath = "m/" + relPath
+	}
+
+	key, err := Derive(keyString, relPath, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	key.DerivationPath = formatDerivationPath(key.ResolvedIndices)
+
+	return key, nil
+}
+
+// DeriveResult is the outcome of deriving a key at a derivation path
+type DeriveResult struct {
+	Path string
+	Key  *Key
+	Err  error
+}
+
+// DeriveManyResults derives keys at each of the derivation paths relative
+// to the input extended key, which is deserialized only once. Results are
+// returned in the order of input paths, each carrying either a key or the
+// error encountered for that path, so that all failures are reported at once.
+func DeriveManyResults(keyString string, paths []string) ([]DeriveResult, error) {
+	bip32Key, err := deserializeExtendedKey(keyString)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]DeriveResult, len(paths))
+	for i, derivationPath := range paths {
+		results[i].Path = derivationPath
+
+		indices, err := parseDerivationPath(derivationPath)
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to parse derivation path %s: %w", derivationPath, err)
+			continue
+		}
+
+		childKey, err := extendedKeyToIndexDerivedExtendedKey(bip32Key, indices)
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to derive extended key at %s: %w", derivationPath, err)
+			continue
+		}
+
+		key, err := derivedExtendedKeyToKey(childKey, "")
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to convert extended key at %s: %w", derivationPath, err)
+			continue
+		}
+
+		key.ResolvedIndices = indices
+		key.DerivationPath = formatDerivationPath(indices)
+		results[i].Key = key
+	}
+
+	return results, nil
+}
+
+// DeriveMany derives keys at each of the derivation paths relative to the
+// input extended key, which is deserialized only once. Keys are returned in
+// the order of input paths. Paths that fail to derive have nil keys and
+// their errors are aggregated into the returned BatchError.
+func DeriveMany(keyString string, paths []string) ([]*Key, error) {
+	results, err := DeriveManyResults(keyString, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, len(results))
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		keys[i] = result.Key
+	}
+
+	if len(errs) > 0 {
+		return keys, &BatchError{Errs: errs, Total: len(paths)}
+	}
+
+	return keys, nil
+}
+
+// Deriver derives child keys of an extended key, which is deserialized
+// only once, making it suitable for deriving a large number of child
+// keys of the same parent, such as addresses of an account branch
+type Deriver struct {
+	key *bip32.Key
+}
+
+// NewDeriver creates a new Deriver for the input extended key
+func NewDeriver(keyString string) (*Deriver, error) {
+	bip32Key, err := deserializeExtendedKey(keyString)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Deriver{key: bip32Key}, nil
+}
+
+// Child derives child key at the index relative to the extended key
+// of the Deriver
+func (d *Deriver) Child(index uint32) (*Key, error) {
+	childKey, err := extendedKeyToIndexDerivedExtendedKey(d.key, []uint32{index})
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive extended key: %w", err)
+	}
+
+	key, err := derivedExtendedKeyToKey(childKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	key.ResolvedIndices = []uint32{index}
+	key.DerivationPath = formatDerivationPath(key.ResolvedIndices)
+
+	return key, nil
+}
+
+// AccountDeriver derives receive and change keys of an account extended key.
+// Receive and change branch keys are derived once and cached, so deriving a
+// key at an index requires a single child key derivation.
+type AccountDeriver struct {
+	receive *bip32.Key
+	change  *bip32.Key
+}
+
+// NewAccountDeriver creates a new AccountDeriver for the input account
+// extended key, such as an account key at m/84h/0h/0h
+func NewAccountDeriver(accountXpub string) (*AccountDeriver, error) {
+	bip32Key, err := deserializeExtendedKey(accountXpub)
+	if err != nil {
+		return nil, err
+	}
+
+	receive, err := extendedKeyToIndexDerivedExtendedKey(bip32Key, []uint32{0})
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive receive branch: %w", err)
+	}
+
+	change, err := extendedKeyToIndexDerivedExtendedKey(bip32Key, []uint32{1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive change branch: %w", err)
+	}
+
+	return &AccountDeriver{receive: receive, change: change}, nil
+}
+
+// Receive derives receive key at the index, i.e., at relative
+// derivation path m/0/index
+func (d *AccountDeriver) Receive(index uint32) (*Key, error) {
+	return branchChild(d.receive, 0, index)
+}
+
+// Change derives change key at the index, i.e., at relative
+// derivation path m/1/index
+func (d *AccountDeriver) Change(index uint32) (*Key, error) {
+	return branchChild(d.change, 1, index)
+}
+
+// branchChild derives child key at the index of a branch key
+func branchChild(branchKey *bip32.Key, branch, index uint32) (*Key, error) {
+	childKey, err := extendedKeyToIndexDerivedExtendedKey(branchKey, []uint32{index})
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive index %d of branch %d: %w", index, branch, err)
+	}
+
+	key, err := derivedExtendedKeyToKey(childKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	key.ResolvedIndices = []uint32{branch, index}
+	key.DerivationPath = formatDerivationPath(key.ResolvedIndices)
+
+	return key, nil
+}
+
+// DeriveAccountAddresses derives receiveCount receive keys followed by
+// changeCount change keys of an account extended key as a single list,
+// with derivation paths m/0/i and m/1/i relative to the account key
+func DeriveAccountAddresses(accountXpub string, receiveCount, changeCount uint32) ([]*Key, error) {
+	receiveKeys, err := DeriveRange(accountXpub, 0, 0, receiveCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive receive keys: %w", err)
+	}
+
+	changeKeys, err := DeriveRange(accountXpub, 1, 0, changeCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive change keys: %w", err)
+	}
+
+	return append(receiveKeys, changeKeys...), nil
+}
+
+// AddressAt derives the primary address at change and index relative to
+// an account extended key, i.e., at relative derivation path m/change/index
+func AddressAt(accountXpub string, change, index uint32) (string, error) {
+	key, err := DeriveIndices(accountXpub, []uint32{change, index})
+	if err != nil {
+		return "", err
+	}
+
+	if len(key.PrimaryAddress) == 0 {
+		return "", fmt.Errorf("no single key address exists for addr type %s", key.AddrType)
+	}
+
+	return key.PrimaryAddress, nil
+}
+
+// DefaultGapLimit is the number of consecutive unused addresses after which
+// account discovery stops scanning a chain per BIP-44
+const DefaultGapLimit = 20
+
+// ScanResult is the outcome of account discovery, with highest used
+// index of each chain, or -1 if no address of the chain is used
+type ScanResult struct {
+	LastUsedReceive int `json:"lastUsedReceive" yaml:"lastUsedReceive"`
+	LastUsedChange  int `json:"lastUsedChange" yaml:"lastUsedChange"`
+}
+
+// ScanAccounts performs BIP-44 account discovery on an account extended key
+// by deriving receive (m/0/i) and change (m/1/i) addresses incrementally and
+// checking each with isUsed, until gapLimit consecutive addresses of a chain
+// are unused. DefaultGapLimit is used when gapLimit is not positive.
+// Skipped indices are reported via WithSkipFunc option.
+func ScanAccounts(accountXpub string, gapLimit int, isUsed func(address string) (bool, error), opts ...DeriveOption) (*ScanResult, error) {
+	options := newDeriveOptions(opts)
+
+	bip32Key, err := deserializeExtendedKey(accountXpub)
+	if err != nil {
+		return nil, err
+	}
+
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+
+	_, lastUsedReceive, err := scanChain(bip32Key, 0, gapLimit, isUsed, options)
+	if err != nil {
+		return nil, err
+	}
+
+	_, lastUsedChange, err := scanChain(bip32Key, 1, gapLimit, isUsed, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScanResult{
+		LastUsedReceive: lastUsedReceive,
+		LastUsedChange:  lastUsedChange,
+	}, nil
+}
+
+// DeriveUntilGap derives keys of a branch of an account extended key, i.e.,
+// at m/branch/i, checking address of each key with isUsed, until gapLimit
+// consecutive addresses are unused, and returns derived keys up to and
+// including the last used one. No keys are returned when none are used.
+// DefaultGapLimit is used when gapLimit is not positive. Skipped indices
+// are reported via WithSkipFunc option.
+func DeriveUntilGap(accountXpub string, branch uint32, gapLimit int, isUsed func(addr string) (bool, error), opts ...DeriveOption) ([]*Key, error) {
+	options := newDeriveOptions(opts)
+
+	bip32Key, err := deserializeExtendedKey(accountXpub)
+	if err != nil {
+		return nil, err
+	}
+
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+
+	keys, _, err := scanChain(bip32Key, branch, gapLimit, isUsed, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// scanChain derives addresses of a chain of an account key until gapLimit
+// consecutive addresses are unused, returning keys up to and including the
+// last used one along with highest used index or -1
+func scanChain(accountKey *bip32.Key, chain uint32, gapLimit int, isUsed func(address string) (bool, error), options *deriveOptions) ([]*Key, int, error) {
+	branchKey, err := extendedKeyToIndexDerivedExtendedKey(accountKey, []uint32{chain})
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to derive chain %d: %w", chain, err)
+	}
+
+	var keys []*Key
+	lastUsed, lastUsedCount, gap := -1, 0, 0
+	for index := uint32(0); gap < gapLimit && index < bip32.FirstHardenedChild; index++ {
+		childKey, err := extendedKeyToIndexDerivedExtendedKey(branchKey, []uint32{index})
+		if err != nil {
+			if errors.Is(err, ErrInvalidChildKey) {
+				options.skip(chain, index, err)
+				continue
+			}
+			return nil, -1, fmt.Errorf("failed to derive index %d of chain %d: %w", index, chain, err)
+		}
+
+		key, err := derivedExtendedKeyToKey(childKey, options.coinType)
+		if err != nil {
+			return nil, -1, err
+		}
+
+		if len(key.PrimaryAddress) == 0 {
+			return nil, -1, fmt.Errorf("no single key address exists for addr type %s", key.AddrType)
+		}
+
+		key.ResolvedIndices = []uint32{chain, index}
+		key.DerivationPath = formatDerivationPath(key.ResolvedIndices)
+		keys = append(keys, key)
+
+		used, err := isUsed(key.PrimaryAddress)
+		if err != nil {
+			return nil, -1, fmt.Errorf("failed to check usage of address %s: %w", key.PrimaryAddress, err)
+		}
+
+		if used {
+			lastUsed, lastUsedCount, gap = int(index), len(keys), 0
+		} else {
+			gap++
+		}
+	}
+
+	return keys[:lastUsedCount], lastUsed, nil
+}
+
+// AllAccountXpubs derives account extended public keys of a seed for each of
+// the single key script types, i.e., xpub at m/44h, ypub at m/49h, zpub at
+// m/84h and xpub at m/86h for taproot, keyed by script type. Testnet keys
+// use corresponding testnet key versions and coin type 1h.
+func AllAccountXpubs(seed []byte, network string, account uint32) (map[string]string, error) {
+	var coinType uint32
+	switch normalizeNetwork(network) {
+	case NetworkTypeMainnet:
+		coinType = 0
+	case NetworkTypeTestnet:
+		coinType = 1
+	default:
+		return nil, fmt.Errorf("invalid or unsupported network: %s. allowed networks are %v", network,
+			[]string{NetworkTypeMainnet, NetworkTypeTestnet},
+		)
+	}
+
+	if account >= bip32.FirstHardenedChild {
+		return nil, fmt.Errorf("account index %d must be less than %d", account, bip32.FirstHardenedChild)
+	}
+
+	accounts := []struct {
+		scriptType string
+		addrType   string
+		purpose    uint32
+	}{
+		{scriptType: AddrTypeP2pkhOrP2sh, addrType: AddrTypeP2pkhOrP2sh, purpose: 44},
+		{scriptType: AddrTypeP2wpkhP2sh, addrType: AddrTypeP2wpkhP2sh, purpose: 49},
+		{scriptType: AddrTypeP2wpkh, addrType: AddrTypeP2wpkh, purpose: 84},
+		// BIP-86 uses xpub key versions
+		{scriptType: AddrTypeP2tr, addrType: AddrTypeP2pkhOrP2sh, purpose: 86},
+	}
+
+	xpubs := make(map[string]string, len(accounts))
+	for _, acct := range accounts {
+		key, err := New(
+			&Config{
+				Seed:     seed,
+				Network:  network,
+				AddrType: acct.addrType,
+				DerivationIndices: []uint32{
+					acct.purpose + bip32.FirstHardenedChild,
+					coinType + bip32.FirstHardenedChild,
+					account + bip32.FirstHardenedChild,
+				},
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive %s account key: %w", acct.scriptType, err)
+		}
+
+		xpubs[acct.scriptType] = key.XPub
+	}
+
+	return xpubs, nil
+}
+
+// CheckConsistency derives the key at the derivation path from the seed via
+// private derivation and also via public derivation of the non-hardened tail
+// of the path from the neutered key at the last hardened level, and returns
+// an error if the resulting pub keys or addresses differ
+func CheckConsistency(seed []byte, path string) error {
+	indices, err := parseDerivationPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+
+	// split path at the last hardened index, the remaining tail
+	// can be derived from the public key
+	split := 0
+	for i, index := range indices {
+		if index >= bip32.FirstHardenedChild {
+			split = i + 1
+		}
+	}
+
+	prvKey, err := New(
+		&Config{
+			Seed:              seed,
+			Network:           NetworkTypeMainnet,
+			DerivationIndices: indices,
+			AddrType:          AddrTypeP2pkhOrP2sh,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to derive key via private derivation: %w", err)
+	}
+
+	branchKey, err := New(
+		&Config{
+			Seed:              seed,
+			Network:           NetworkTypeMainnet,
+			DerivationIndices: indices[:split],
+			AddrType:          AddrTypeP2pkhOrP2sh,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to derive branch key: %w", err)
+	}
+
+	pubKey, err := DeriveIndices(branchKey.XPub, indices[split:])
+	if err != nil {
+		return fmt.Errorf("failed to derive key via public derivation: %w", err)
+	}
+
+	if pubKey.XPub != prvKey.XPub {
+		return fmt.Errorf("extended pub key mismatch at %s: private derivation %s, public derivation %s",
+			prvKey.DerivationPath, prvKey.XPub, pubKey.XPub)
+	}
+
+	if pubKey.Addr != prvKey.Addr {
+		return fmt.Errorf("address mismatch at %s: private derivation %s, public derivation %s",
+			prvKey.DerivationPath, prvKey.Addr, pubKey.Addr)
+	}
+
+	return nil
+}
+
+// DeriveMultiSeed derives keys at the same derivation path for each of the
+// seeds concurrently, using one goroutine per CPU, and returns keys aligned
+// by index with input seeds. An error deriving from any seed fails the call
+// and reports the index of the first failing seed.
+func DeriveMultiSeed(seeds [][]byte, path, network, scriptType string) ([]*Key, error) {
+	workers := runtime.NumCPU()
+	if workers > len(seeds) {
+		workers = len(seeds)
+	}
+
+	keys := make([]*Key, len(seeds))
+	errs := make([]error, len(seeds))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				keys[index], errs[index] = New(
+					&Config{
+						Seed:           seeds[index],
+						Network:        network,
+						DerivationPath: path,
+						AddrType:       scriptType,
+					},
+				)
+			}
+		}()
+	}
+
+	for i := range seeds {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key for seed %d: %w", i, err)
+		}
+	}
+
+	return keys, nil
+}
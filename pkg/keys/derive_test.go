@@ -0,0 +1,527 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip32"
+)
+
+// BIP-84 account zpub of mnemonic:
+// abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about
+const testZpubAbandonAbout = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+
+func TestDeriver_Child(t *testing.T) {
+	branch, err := Derive(testZpubAbandonAbout, "m/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deriver, err := NewDeriver(branch.XPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := uint32(0); i < 5; i++ {
+		child, err := deriver.Child(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := Derive(testZpubAbandonAbout, fmt.Sprintf("m/0/%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if child.Addr != key.Addr {
+			t.Fatal("expected", key.Addr, ", got", child.Addr, ", for index", i)
+		}
+	}
+}
+
+func BenchmarkDerive(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := Derive(testZpubAbandonAbout, fmt.Sprintf("m/0/%d", i%1000)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDeriver_Child(b *testing.B) {
+	branch, err := Derive(testZpubAbandonAbout, "m/0")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	deriver, err := NewDeriver(branch.XPub)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := deriver.Child(uint32(i % 1000)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+func TestAddressAt(t *testing.T) {
+	addr, err := AddressAt(testZpubAbandonAbout, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+	if addr != expected {
+		t.Fatal("expected", expected, ", got", addr)
+	}
+}
+
+func TestCheckConsistency(t *testing.T) {
+	for _, path := range []string{
+		"m",
+		"m/0",
+		"m/0/1/2",
+		"m/44h/0h/0h",
+		"m/44h/0h/0h/0/0",
+		"m/84h/0h/0h/1/19",
+		"m/0h/1/2h/2/1000000000",
+	} {
+		if err := CheckConsistency(TestSeed(), path); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDeriveRange_TooLarge(t *testing.T) {
+	if _, err := DeriveRange(testZpubAbandonAbout, 0, 0, MaxRangeCount+1); !errors.Is(err, ErrRangeTooLarge) {
+		t.Fatal("expected ErrRangeTooLarge, got", err)
+	}
+
+	if _, err := DeriveRange(testZpubAbandonAbout, 0, 0, 3, WithMaxRangeCount(2)); !errors.Is(err, ErrRangeTooLarge) {
+		t.Fatal("expected ErrRangeTooLarge, got", err)
+	}
+
+	if keys, err := DeriveRange(testZpubAbandonAbout, 0, 0, 2, WithMaxRangeCount(2)); err != nil || len(keys) != 2 {
+		t.Fatal("expected 2 keys within custom limit, got", len(keys), err)
+	}
+
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/84h/0h/0h",
+			AddrType:       AddrTypeSegWitNative,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DeriveRange(key.XPrv, 0, bip32.FirstHardenedChild-1, 2); !errors.Is(err, ErrRangeTooLarge) {
+		t.Fatal("expected ErrRangeTooLarge, got", err)
+	}
+
+	// ranges ending at the last index of an index space are derived
+	// without wrapping around
+	for _, start := range []uint32{bip32.FirstHardenedChild - 2, 1<<32 - 2} {
+		keys, err := DeriveRange(key.XPrv, 0, start, 2)
+		if err != nil || len(keys) != 2 {
+			t.Fatal("expected 2 keys, got", len(keys), err)
+		}
+
+		if keys[1].ResolvedIndices[1] != start+1 {
+			t.Fatal("expected last index", start+1, ", got", keys[1].ResolvedIndices[1])
+		}
+	}
+
+	if _, err := DeriveRange(key.XPrv, 0, 1<<32-2, 3); !errors.Is(err, ErrRangeTooLarge) {
+		t.Fatal("expected ErrRangeTooLarge, got", err)
+	}
+}
+
+func TestDeriveMultiSeed(t *testing.T) {
+	seeds := make([][]byte, 8)
+	for i := range seeds {
+		seeds[i] = TestSeed()
+	}
+	seeds[5] = append(TestSeed()[1:], 0x00)
+
+	keys, err := DeriveMultiSeed(seeds, "m/84h/0h/0h/0/0", NetworkTypeMainnet, AddrTypeSegWitNative)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != len(seeds) {
+		t.Fatal("expected", len(seeds), "keys, got", len(keys))
+	}
+
+	expected := "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+	for i, key := range keys {
+		if (key.Addr == expected) == (i == 5) {
+			t.Fatal("unexpected address", key.Addr, "for seed", i)
+		}
+	}
+}
+
+func TestDeriveRange_Paths(t *testing.T) {
+	var skipped []uint32
+	keys, err := DeriveRange(testZpubAbandonAbout, 0, 0, 3,
+		WithSkipFunc(func(branch, index uint32, err error) {
+			skipped = append(skipped, index)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 3 || len(skipped) != 0 {
+		t.Fatal("expected 3 keys and no skipped indices, got", len(keys), "keys and", skipped)
+	}
+
+	for i, key := range keys {
+		if expected := fmt.Sprintf("m/0/%d", i); key.DerivationPath != expected {
+			t.Fatal("expected", expected, ", got", key.DerivationPath)
+		}
+	}
+}
+
+func TestScanAccounts(t *testing.T) {
+	const gapLimit = 5
+
+	used := make(map[string]bool)
+	for _, branch := range []uint32{0, 1} {
+		keys, err := DeriveRange(testZpubAbandonAbout, branch, 0, gapLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		used[keys[0].PrimaryAddress] = true
+		used[keys[gapLimit-1].PrimaryAddress] = true
+	}
+
+	var checked int
+	result, err := ScanAccounts(testZpubAbandonAbout, gapLimit, func(address string) (bool, error) {
+		checked++
+		return used[address], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.LastUsedReceive != gapLimit-1 || result.LastUsedChange != gapLimit-1 {
+		t.Fatal("expected last used receive and change index", gapLimit-1, ", got",
+			result.LastUsedReceive, "and", result.LastUsedChange)
+	}
+
+	// each chain is scanned through gapLimit unused addresses past the
+	// last used one, i.e., indices 0 through 2*gapLimit-1
+	if expected := 2 * 2 * gapLimit; checked != expected {
+		t.Fatal("expected", expected, "addresses to be checked, got", checked)
+	}
+}
+
+func TestDeriveUntilGap(t *testing.T) {
+	const gapLimit = 5
+
+	receiveKeys, err := DeriveRange(testZpubAbandonAbout, 0, 0, 2*gapLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		used     []int
+		expected int
+	}{
+		{"nothing used", nil, 0},
+		{"index 0 used", []int{0}, 1},
+		// indices 1 through 5 are unused, exhausting the gap limit
+		{"used past gap", []int{0, gapLimit + 1}, 1},
+		// indices 1 through 4 are unused, index 5 is still scanned
+		{"used at gap boundary", []int{0, gapLimit}, gapLimit + 1},
+	}
+
+	for _, test := range tests {
+		used := make(map[string]bool)
+		for _, index := range test.used {
+			used[receiveKeys[index].PrimaryAddress] = true
+		}
+
+		keys, err := DeriveUntilGap(testZpubAbandonAbout, 0, gapLimit, func(addr string) (bool, error) {
+			return used[addr], nil
+		})
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+
+		if len(keys) != test.expected {
+			t.Fatal(test.name, "expected", test.expected, "keys, got", len(keys))
+		}
+
+		for i, key := range keys {
+			if expected := fmt.Sprintf("m/0/%d", i); key.DerivationPath != expected {
+				t.Fatal(test.name, "expected", expected, ", got", key.DerivationPath)
+			}
+		}
+	}
+}
+
+// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+func TestDeriveAccountAddresses(t *testing.T) {
+	keys, err := DeriveAccountAddresses(testZpubAbandonAbout, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		derivationPath string
+		addr           string
+	}{
+		{"m/0/0", "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"},
+		{"m/0/1", "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g"},
+		{"m/1/0", "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el"},
+	}
+
+	if len(keys) != len(expected) {
+		t.Fatal("expected", len(expected), "keys, got", len(keys))
+	}
+
+	for i, key := range keys {
+		if key.DerivationPath != expected[i].derivationPath || key.Addr != expected[i].addr {
+			t.Fatal("expected", expected[i].derivationPath, expected[i].addr, ", got", key.DerivationPath, key.Addr)
+		}
+	}
+}
+
+// account extended keys for mnemonic abandon ... about
+// https://github.com/bitcoin/bips/blob/master/bip-0049.mediawiki#test-vectors
+// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+func TestAllAccountXpubs(t *testing.T) {
+	xpubs, err := AllAccountXpubs(TestSeed(), NetworkTypeMainnet, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		AddrTypeP2pkhOrP2sh: "xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj",
+		AddrTypeP2wpkhP2sh:  "ypub6Ww3ibxVfGzLrAH1PNcjyAWenMTbbAosGNB6VvmSEgytSER9azLDWCxoJwW7Ke7icmizBMXrzBx9979FfaHxHcrArf3zbeJJJUZPf663zsP",
+		AddrTypeP2wpkh:      testZpubAbandonAbout,
+		AddrTypeP2tr:        "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ",
+	}
+
+	if len(xpubs) != len(expected) {
+		t.Fatal("expected", len(expected), "xpubs, got", len(xpubs))
+	}
+
+	for scriptType, xpub := range expected {
+		if xpubs[scriptType] != xpub {
+			t.Fatal(scriptType, "expected", xpub, ", got", xpubs[scriptType])
+		}
+	}
+
+	if _, err := AllAccountXpubs(TestSeed(), NetworkTypeMainnet, bip32.FirstHardenedChild); err == nil {
+		t.Fatal("expected error for hardened account index")
+	}
+}
+
+func TestDerive_CoinType(t *testing.T) {
+	zecMaster, err := New(&Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m",
+		AddrType:       AddrTypeLegacy,
+		CoinType:       CoinTypeZec,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zec shares xprv key version with btc
+	key, err := Derive(zecMaster.XPrv, "m/44h/133h/0h/0/0", WithCoinType(CoinTypeZec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.CoinType != CoinTypeZec || key.Addr != "t1XVXWCvpMgBvUaed4XDqWtgQgJSu1Ghz7F" {
+		t.Fatal("expected zec address t1XVXWCvpMgBvUaed4XDqWtgQgJSu1Ghz7F, got", key.CoinType, key.Addr)
+	}
+
+	key, err = Derive(zecMaster.XPrv, "m/44h/133h/0h/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.CoinType != CoinTypeBtc || !strings.HasPrefix(key.Addr, "1") {
+		t.Fatal("expected btc address without coin type, got", key.CoinType, key.Addr)
+	}
+
+	ltcConfig := &Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m/84h/2h/0h",
+		AddrType:       AddrTypeP2wpkh,
+		CoinType:       CoinTypeLtc,
+	}
+
+	ltcAccount, err := New(ltcConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ltcConfig.DerivationPath = "m/84h/2h/0h/0/0"
+	expected, err := New(ltcConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ltc shares zprv key version with btc
+	key, err = Derive(ltcAccount.XPrv, "m/0/0", WithCoinType(CoinTypeLtc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.CoinType != CoinTypeLtc || key.Addr != expected.Addr || !strings.HasPrefix(key.Addr, "ltc1q") {
+		t.Fatal("expected ltc address", expected.Addr, ", got", key.CoinType, key.Addr)
+	}
+
+	keys, err := DeriveRange(ltcAccount.XPub, 0, 0, 1, WithCoinType(CoinTypeLtc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys[0].Addr != expected.Addr {
+		t.Fatal("expected ltc address", expected.Addr, ", got", keys[0].Addr)
+	}
+
+	// zprv is not registered for doge
+	if _, err := Derive(ltcAccount.XPrv, "m/0/0", WithCoinType(CoinTypeDoge)); err == nil {
+		t.Fatal("expected error deriving zprv as doge")
+	}
+
+	ltcLegacy, err := New(&Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m/44h/2h/0h",
+		AddrType:       AddrTypeLegacy,
+		CoinType:       CoinTypeLtc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ltpv is unique to ltc and cannot be derived as btc
+	if _, err := Derive(ltcLegacy.XPrv, "m/0/0", WithCoinType(CoinTypeBtc)); err == nil {
+		t.Fatal("expected error deriving ltc key version as btc")
+	}
+
+	key, err = Derive(ltcLegacy.XPrv, "m/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Addr != "LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez" {
+		t.Fatal("expected LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez, got", key.Addr)
+	}
+}
+
+func TestDeriveRelative(t *testing.T) {
+	config := &Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m",
+		AddrType:       AddrTypeP2wpkh,
+	}
+
+	master, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := Derive(master.XPrv, "m/84h/0h/0h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := Derive(master.XPrv, "m/84h/0h/0h/0/5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, relPath := range []string{"0/5", "m/0/5", "/0/5/"} {
+		key, err := DeriveRelative(account.XPrv, relPath)
+		if err != nil {
+			t.Fatal(relPath, err)
+		}
+
+		if key.XPrv != expected.XPrv || key.Addr != expected.Addr {
+			t.Fatal(relPath, "expected", expected.Addr, ", got", key.Addr)
+		}
+
+		if key.DerivationPath != "m/0/5" {
+			t.Fatal(relPath, "expected derivation path m/0/5, got", key.DerivationPath)
+		}
+	}
+
+	if _, err := DeriveRelative(account.XPub, "0h/5"); !errors.Is(err, ErrHardenedFromPublic) {
+		t.Fatal("expected ErrHardenedFromPublic, got", err)
+	}
+
+	key, err := DeriveRelative(account.XPub, "0/5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.XPub != expected.XPub || key.Addr != expected.Addr || key.IsPrivate {
+		t.Fatal("expected", expected.Addr, ", got", key.Addr)
+	}
+}
+
+func TestAccountDeriver(t *testing.T) {
+	const receiveCount, changeCount = 2, 1
+
+	expected, err := DeriveAccountAddresses(testZpubAbandonAbout, receiveCount, changeCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deriver, err := NewAccountDeriver(testZpubAbandonAbout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []*Key
+	for i := uint32(0); i < receiveCount; i++ {
+		key, err := deriver.Receive(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+
+	for i := uint32(0); i < changeCount; i++ {
+		key, err := deriver.Change(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) != len(expected) {
+		t.Fatal("expected", len(expected), "keys, got", len(keys))
+	}
+
+	for i, key := range keys {
+		if key.DerivationPath != expected[i].DerivationPath || key.Addr != expected[i].Addr || key.XPub != expected[i].XPub {
+			t.Fatal("expected", expected[i].DerivationPath, expected[i].Addr, ", got", key.DerivationPath, key.Addr)
+		}
+	}
+
+	if keys[0].Addr != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+		t.Fatal("expected bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu, got", keys[0].Addr)
+	}
+}
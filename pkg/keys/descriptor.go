@@ -0,0 +1,238 @@
+package keys
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// descriptorScripts maps single key output descriptor script expressions
+// to their script types
+// https://github.com/bitcoin/bips/blob/master/bip-0380.mediawiki
+var descriptorScripts = []struct {
+	prefix     string
+	suffix     string
+	scriptType string
+}{
+	{prefix: "pkh(", suffix: ")", scriptType: AddrTypeP2pkhOrP2sh},
+	{prefix: "sh(wpkh(", suffix: "))", scriptType: AddrTypeP2wpkhP2sh},
+	{prefix: "wpkh(", suffix: ")", scriptType: AddrTypeP2wpkh},
+	{prefix: "tr(", suffix: ")", scriptType: AddrTypeP2tr},
+}
+
+// AddressFromDescriptor generates the address of a single key output descriptor,
+// such as wpkh([fp/84h/0h/0h]xpub.../0/*), at the index substituted for its
+// wildcard. Supported descriptors are pkh, sh(wpkh), wpkh and tr with an
+// extended key, optional key origin and optional checksum. Other descriptors,
+// including ones with raw hex pub keys, return ErrUnsupportedDescriptor.
+func AddressFromDescriptor(descriptor string, index uint32) (string, error) {
+	key, err := descriptorKey(descriptor, index)
+	if err != nil {
+		return "", err
+	}
+
+	return key.Addr, nil
+}
+
+// descriptorKey derives the key of a single key output descriptor at the
+// index substituted for its wildcard. Address and addr type of the key are
+// set per descriptor script type and derivation path of the key is set
+// relative to the master key when descriptor carries a key origin.
+func descriptorKey(descriptor string, index uint32) (*Key, error) {
+	if err := checkInputLength(descriptor); err != nil {
+		return nil, err
+	}
+
+	descriptor = tidyInput(descriptor)
+	if i := strings.Index(descriptor, "#"); i >= 0 {
+		checksum, err := DescriptorChecksum(descriptor[:i])
+		if err != nil {
+			return nil, err
+		}
+
+		if descriptor[i+1:] != checksum {
+			return nil, fmt.Errorf("invalid descriptor checksum %s, expected %s", descriptor[i+1:], checksum)
+		}
+
+		descriptor = descriptor[:i]
+	}
+
+	var scriptType, keyExpression string
+	for _, descriptorScript := range descriptorScripts {
+		if strings.HasPrefix(descriptor, descriptorScript.prefix) &&
+			strings.HasSuffix(descriptor, descriptorScript.suffix) {
+			scriptType = descriptorScript.scriptType
+			keyExpression = strings.TrimSuffix(
+				strings.TrimPrefix(descriptor, descriptorScript.prefix),
+				descriptorScript.suffix,
+			)
+			break
+		}
+	}
+
+	if len(scriptType) == 0 {
+		return nil, fmt.Errorf("%w, only pkh, sh(wpkh), wpkh and tr descriptors are supported", ErrUnsupportedDescriptor)
+	}
+
+	// key origin is informational and is not needed for derivation,
+	// however, it allows reporting derivation path from master key
+	var originIndices []uint32
+	if strings.HasPrefix(keyExpression, "[") {
+		i := strings.Index(keyExpression, "]")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid key origin in descriptor, missing closing bracket")
+		}
+
+		originParts := strings.Split(keyExpression[1:i], "/")
+		if len(originParts) > 1 {
+			var err error
+			originIndices, err = parseDerivationPath(strings.Join(append([]string{"m"}, originParts[1:]...), "/"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse key origin derivation path: %w", err)
+			}
+		}
+
+		keyExpression = keyExpression[i+1:]
+	}
+
+	parts := strings.Split(keyExpression, "/")
+	keyString := parts[0]
+
+	// raw hex pub keys and wif keys carry no chain code to derive from
+	if len(base58.Decode(keyString)) != 82 {
+		return nil, fmt.Errorf("%w, only extended keys are supported in key expressions", ErrUnsupportedDescriptor)
+	}
+	for i, part := range parts[1:] {
+		switch part {
+		case "*":
+			parts[i+1] = strconv.FormatUint(uint64(index), 10)
+		case "*h", "*'":
+			return nil, fmt.Errorf("hardened wildcard is not supported")
+		}
+	}
+
+	key, err := Derive(keyString, strings.Join(append([]string{"m"}, parts[1:]...), "/"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive descriptor key: %w", err)
+	}
+
+	pubKeyBytes, err := key.PubKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pub key: %w", err)
+	}
+
+	params := networkParams(key.CoinType, key.Network)
+	if params == nil {
+		return nil, fmt.Errorf("%w for coin type %s on %s", ErrMissingNetworkParams, key.CoinType, key.Network)
+	}
+
+	addr, err := scriptTypeAddress(pub.SerializeCompressed(), scriptType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Addr, key.PrimaryAddress, key.AddrType = addr, addr, scriptType
+	key.segWitNested, key.segWitBech32 = "", ""
+	if len(originIndices) > 0 {
+		key.ResolvedIndices = append(originIndices, key.ResolvedIndices...)
+	}
+	key.DerivationPath = formatDerivationPath(key.ResolvedIndices)
+
+	return key, nil
+}
+
+// descriptor checksum char sets
+// https://github.com/bitcoin/bips/blob/master/bip-0380.mediawiki#checksum
+const (
+	descriptorInputCharSet    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharSet = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// descriptorPolyMod is the BCH code polymod of descriptor checksum
+func descriptorPolyMod(c uint64, val int) uint64 {
+	c0 := c >> 35
+	c = ((c & 0x7ffffffff) << 5) ^ uint64(val)
+	if c0&1 != 0 {
+		c ^= 0xf5dee51989
+	}
+	if c0&2 != 0 {
+		c ^= 0xa9fdca3312
+	}
+	if c0&4 != 0 {
+		c ^= 0x1bab10e32d
+	}
+	if c0&8 != 0 {
+		c ^= 0x3706b1677a
+	}
+	if c0&16 != 0 {
+		c ^= 0x644d626ffd
+	}
+	return c
+}
+
+// DescriptorChecksum computes the eight char BIP-380 checksum of an
+// output descriptor without checksum
+func DescriptorChecksum(descriptor string) (string, error) {
+	c := uint64(1)
+	cls, clsCount := 0, 0
+	for _, ch := range descriptor {
+		pos := strings.IndexRune(descriptorInputCharSet, ch)
+		if pos < 0 {
+			return "", fmt.Errorf("invalid char %q in descriptor", ch)
+		}
+
+		// emit a symbol for the position inside the group, for every char
+		c = descriptorPolyMod(c, pos&31)
+
+		// accumulate the group numbers
+		cls = cls*3 + pos>>5
+		clsCount++
+		if clsCount == 3 {
+			// emit an extra symbol representing the group numbers, for every 3 chars
+			c = descriptorPolyMod(c, cls)
+			cls, clsCount = 0, 0
+		}
+	}
+
+	if clsCount > 0 {
+		c = descriptorPolyMod(c, cls)
+	}
+
+	// shift further to determine the checksum
+	for i := 0; i < 8; i++ {
+		c = descriptorPolyMod(c, 0)
+	}
+
+	// prevent appending zeroes from not affecting the checksum
+	c ^= 1
+
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharSet[(c>>(5*(7-i)))&31]
+	}
+
+	return string(checksum), nil
+}
+
+// AppendChecksum appends BIP-380 checksum to an output descriptor,
+// replacing an existing checksum if any
+func AppendChecksum(descriptor string) (string, error) {
+	if i := strings.Index(descriptor, "#"); i >= 0 {
+		descriptor = descriptor[:i]
+	}
+
+	checksum, err := DescriptorChecksum(descriptor)
+	if err != nil {
+		return "", err
+	}
+
+	return descriptor + "#" + checksum, nil
+}
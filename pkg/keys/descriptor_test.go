@@ -0,0 +1,115 @@
+package keys
+
+import (
+	"testing"
+)
+
+// https://github.com/bitcoin/bips/blob/master/bip-0380.mediawiki#test-vectors
+func TestDescriptorChecksum(t *testing.T) {
+	tests := []struct {
+		descriptor string
+		checksum   string
+	}{
+		{"raw(deadbeef)", "89f8spxm"},
+		{"addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)", "02wpgw69"},
+	}
+
+	for _, test := range tests {
+		checksum, err := DescriptorChecksum(test.descriptor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if checksum != test.checksum {
+			t.Fatal("expected", test.checksum, ", got", checksum)
+		}
+
+		descriptor, err := AppendChecksum(test.descriptor + "#xxxxxxxx")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if descriptor != test.descriptor+"#"+test.checksum {
+			t.Fatal("expected", test.descriptor+"#"+test.checksum, ", got", descriptor)
+		}
+	}
+}
+
+// test vectors of BIP-84 and BIP-86 for mnemonic abandon ... about
+// https://github.com/bitcoin/bips/blob/master/bip-0084.mediawiki#test-vectors
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+func TestAddressFromDescriptor(t *testing.T) {
+	bip86Xpub := "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+
+	// descriptors only accept xpub key versions
+	bip84Key, err := deserializeExtendedKey(testZpubAbandonAbout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bip84Key.Version = mustDecodeHex(xpub)
+	bip84Xpub := bip84Key.B58Serialize()
+
+	tests := []struct {
+		descriptor string
+		addrs      []string
+	}{
+		{
+			descriptor: "wpkh(" + bip84Xpub + "/0/*)",
+			addrs: []string{
+				"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+				"bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g",
+			},
+		},
+		{
+			descriptor: "wpkh([73c5da0a/84h/0h/0h]" + bip84Xpub + "/0/*)",
+			addrs: []string{
+				"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+				"bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g",
+			},
+		},
+		{
+			descriptor: "tr(" + bip86Xpub + "/0/*)",
+			addrs: []string{
+				"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+				"bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh",
+			},
+		},
+		{
+			descriptor: "tr([73c5da0a/86h/0h/0h]" + bip86Xpub + "/0/*)",
+			addrs: []string{
+				"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+				"bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		withChecksum, err := AppendChecksum(test.descriptor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, descriptor := range []string{test.descriptor, withChecksum} {
+			for i, expected := range test.addrs {
+				addr, err := AddressFromDescriptor(descriptor, uint32(i))
+				if err != nil {
+					t.Fatal(descriptor, err)
+				}
+
+				if addr != expected {
+					t.Fatal(descriptor, "index", i, "expected", expected, ", got", addr)
+				}
+			}
+		}
+	}
+
+	// corrupt the checksum
+	descriptor, err := AppendChecksum(tests[0].descriptor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AddressFromDescriptor(descriptor[:len(descriptor)-1]+"x", 0); err == nil {
+		t.Fatal("expected error for invalid descriptor checksum")
+	}
+}
@@ -0,0 +1,143 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// electrum v2 seed version prefixes
+// https://electrum.readthedocs.io/en/latest/seedphrase.html#version-number
+const (
+	electrumSeedPrefixStandard  = "01"
+	electrumSeedPrefixSegWit    = "100"
+	electrumSeedPrefix2FA       = "101"
+	electrumSeedPrefix2FASegWit = "102"
+)
+
+// ElectrumSeedToSeed derives the bip32 seed from an Electrum v2 seed phrase
+// and optional passphrase. Electrum seeds are not BIP-39 mnemonics, they carry
+// a version in the hash of the phrase instead of a checksum and use a different
+// salt during key stretching.
+func ElectrumSeedToSeed(phrase, passphrase string) ([]byte, error) {
+	phrase = normalizeElectrumText(phrase)
+	passphrase = normalizeElectrumText(passphrase)
+
+	mac := hmac.New(sha512.New, []byte("Seed version"))
+	mac.Write([]byte(phrase))
+	version := hex.EncodeToString(mac.Sum(nil))
+
+	switch {
+	case strings.HasPrefix(version, electrumSeedPrefixStandard),
+		strings.HasPrefix(version, electrumSeedPrefixSegWit),
+		strings.HasPrefix(version, electrumSeedPrefix2FA),
+		strings.HasPrefix(version, electrumSeedPrefix2FASegWit):
+	default:
+		return nil, fmt.Errorf("input is not a valid electrum v2 seed phrase")
+	}
+
+	return pbkdf2.Key([]byte(phrase), []byte("electrum"+passphrase), 2048, 64, sha512.New), nil
+}
+
+// normalizeElectrumText normalizes text the same way as Electrum
+// https://github.com/spesmilo/electrum/blob/master/electrum/mnemonic.py
+func normalizeElectrumText(text string) string {
+	text = strings.ToLower(norm.NFKD.String(text))
+
+	// remove accents
+	var sb strings.Builder
+	for _, r := range text {
+		if norm.NFD.PropertiesString(string(r)).CCC() != 0 {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	// normalize whitespace
+	runes := []rune(strings.Join(strings.Fields(sb.String()), " "))
+
+	// remove whitespace between CJK chars
+	sb.Reset()
+	for i, r := range runes {
+		if unicode.IsSpace(r) && i > 0 && i < len(runes)-1 && isCJK(runes[i-1]) && isCJK(runes[i+1]) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// ImportElectrumMasterPublicKey decodes a master public key exported by
+// Electrum 2.0 and later, i.e., xpub of standard wallets, zpub or ypub of
+// segwit wallets and Zpub or Ypub of multisig wallets, along with their
+// testnet counterparts. Electrum derives standard wallet keys directly from
+// the root key and segwit and multisig wallet keys from m/0h and m/1h
+// respectively, therefore, master public keys at depth 0 or 1 carry no
+// BIP-44 purpose or coin type levels. Derivation path of the key is set
+// relative to the root key for such keys and is left empty for deeper keys,
+// such as those of wallets restored from BIP-39 seeds, since intermediate
+// indices cannot be recovered from the key. Addresses of the wallet are
+// derived at m/0/i and m/1/i relative to the master public key.
+// Master public keys of Electrum 1.x wallets are not BIP-32 extended keys
+// and are not supported.
+func ImportElectrumMasterPublicKey(mpk string) (*Key, error) {
+	mpk = strings.TrimSpace(mpk)
+
+	// electrum 1.x master public keys are 64 byte hex encoded
+	// uncompressed pub keys without the 04 prefix
+	if len(mpk) == 128 {
+		if _, err := hex.DecodeString(mpk); err == nil {
+			return nil, fmt.Errorf("electrum 1.x master public keys are not supported")
+		}
+	}
+
+	bip32Key, err := deserializeExtendedKey(mpk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize master public key: %w", err)
+	}
+
+	if bip32Key.IsPrivate {
+		return nil, fmt.Errorf("master public key cannot be a private key")
+	}
+
+	key, err := derivedExtendedKeyToKey(bip32Key, "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch bip32Key.Depth {
+	case 0:
+		key.DerivationPath = formatDerivationPath(nil)
+	case 1:
+		key.ResolvedIndices = []uint32{binary.BigEndian.Uint32(bip32Key.ChildNumber)}
+		key.DerivationPath = formatDerivationPath(key.ResolvedIndices)
+	default:
+		key.DerivationPath = ""
+	}
+
+	return key, nil
+}
+
+// ExportElectrumMasterPublicKey returns the extended pub key of the key
+// serialized with the key version of its script type, which can be imported
+// into Electrum 2.0 and later as a watching-only wallet
+func ExportElectrumMasterPublicKey(key *Key) (string, error) {
+	mpk, err := key.SerializeXPub()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize extended pub key: %w", err)
+	}
+
+	return mpk, nil
+}
@@ -0,0 +1,107 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip32"
+)
+
+// https://github.com/spesmilo/electrum/blob/master/tests/test_mnemonic.py
+func TestElectrumSeedToSeed(t *testing.T) {
+	seed, err := ElectrumSeedToSeed("wild father tree among universe such mobile favorite target dynamic credit identify", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "aac2a6302e48577ab4b46f23dbae0774e2e62c796f797d0a1b5faeb528301e3064342dafb79069e7c4c6b8c38ae11d7a973bec0d4f70626f8cc5184a8d0b0756"
+	if hex.EncodeToString(seed) != expected {
+		t.Fatal("expected", expected, ", got", hex.EncodeToString(seed))
+	}
+
+	// bip39 mnemonic is not a valid electrum seed
+	if _, err := ElectrumSeedToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", ""); err == nil {
+		t.Fatal("expected error for bip39 mnemonic")
+	}
+}
+
+// https://github.com/spesmilo/electrum/blob/master/tests/test_wallet_vertical.py
+func TestElectrumSeedToSeed_SegWit(t *testing.T) {
+	seed, err := ElectrumSeedToSeed("bitter grass shiver impose acquire brush forget axis eager alone wine silver", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// electrum segwit wallets derive addresses from m/0h
+	key, err := New(
+		&Config{
+			Seed:           seed,
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/0h/0/0",
+			AddrType:       AddrTypeP2wpkh,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "bc1q3g5tmkmlvxryhh843v4dz026avatc0zzr6h3af"
+	if key.Addr != expected {
+		t.Fatal("expected", expected, ", got", key.Addr)
+	}
+}
+
+func TestImportElectrumMasterPublicKey(t *testing.T) {
+	master, err := bip32.NewMasterKey(TestSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mpk            string
+		derivationPath string
+		addrType       string
+	}{
+		{master.PublicKey().B58Serialize(), "m", AddrTypeLegacy},
+		// https://github.com/spesmilo/electrum/blob/master/tests/test_wallet_vertical.py
+		{"zpub6nsHdRuY92FsMKdbn9BfjBCG6X8pyhCibNP6uDvpnw2cyrVhecvHRMa3Ne8kdJZxjxgwnpbHLkcR4bfnhHy6auHPJyDTQ3kianeuVLdkCYQ", "m/0h", AddrTypeSegWitNative + ", " + AddrTypeBech32},
+		{"Zpub6ymNkfdyhypEmtnyioeeZFY4pKB6C3tJue2mpVCNAhs2c34cR2JZWURyBM6EBjnryRkvfQbQDxzvBmHYzX83jPQ49SKroTDiUWvn5EyUVFe", "m/0h", AddrTypeP2wsh},
+		{testZpubAbandonAbout, "", AddrTypeSegWitNative + ", " + AddrTypeBech32},
+	}
+
+	for _, test := range tests {
+		key, err := ImportElectrumMasterPublicKey(test.mpk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key.DerivationPath != test.derivationPath {
+			t.Fatal("expected derivation path", test.derivationPath, ", got", key.DerivationPath)
+		}
+
+		if key.AddrType != test.addrType {
+			t.Fatal("expected addr type", test.addrType, ", got", key.AddrType)
+		}
+	}
+
+	// first receive address of electrum segwit wallet
+	key, err := Derive(tests[1].mpk, "m/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "bc1q3g5tmkmlvxryhh843v4dz026avatc0zzr6h3af"
+	if key.Addr != expected {
+		t.Fatal("expected", expected, ", got", key.Addr)
+	}
+
+	// electrum 1.x master public key
+	if _, err := ImportElectrumMasterPublicKey(strings.Repeat("ab", 64)); err == nil {
+		t.Fatal("expected error for electrum 1.x master public key")
+	}
+
+	if _, err := ImportElectrumMasterPublicKey(master.B58Serialize()); err == nil {
+		t.Fatal("expected error for private key")
+	}
+}
@@ -0,0 +1,121 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidChildKey is returned when a child index yields an invalid
+// child key, i.e., when the derived private key is zero or not less than
+// the curve order, or when the derived public key is the point at infinity.
+// Per BIP-32, such an index should be skipped in favor of the next one.
+var ErrInvalidChildKey = errors.New("invalid child key")
+
+// ErrHardenedFromPublic is returned when a hardened child key is requested
+// from a public parent key
+var ErrHardenedFromPublic = errors.New("cannot derive hardened child key from public key")
+
+// ErrMissingNetworkParams is returned when no chain params are registered
+// for the requested coin type and network combination
+var ErrMissingNetworkParams = errors.New("missing network params")
+
+// BatchError aggregates errors of batch operations, such as DeriveMany,
+// so that all failures are reported at once
+type BatchError struct {
+	Errs  []error
+	Total int
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d of %d failed: %s", len(e.Errs), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns aggregated errors, which errors.Is and errors.As
+// walk as of go 1.20
+func (e *BatchError) Unwrap() []error {
+	return e.Errs
+}
+
+// Is reports whether any of the aggregated errors matches target,
+// allowing errors.Is to match them on go versions prior to 1.20
+func (e *BatchError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the aggregated errors that matches target,
+// allowing errors.As to match them on go versions prior to 1.20
+func (e *BatchError) As(target any) bool {
+	for _, err := range e.Errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrInputTooLong is returned when input exceeds MaxInputLength
+var ErrInputTooLong = errors.New("input too long")
+
+// ErrDepthOverflow is returned when derivation would exceed the max
+// extended key depth of 255
+var ErrDepthOverflow = errors.New("max key depth exceeded")
+
+// ErrInvalidMnemonicWordCount is returned when a mnemonic does not
+// have 12, 15, 18, 21 or 24 words
+var ErrInvalidMnemonicWordCount = errors.New("invalid mnemonic word count")
+
+// ErrUnknownMnemonicWord is returned when a mnemonic word does not
+// belong to the wordlist
+var ErrUnknownMnemonicWord = errors.New("unknown mnemonic word")
+
+// ErrInvalidMnemonicChecksum is returned when mnemonic checksum bits
+// do not match its entropy
+var ErrInvalidMnemonicChecksum = errors.New("invalid mnemonic checksum")
+
+// ErrAddressMismatch is returned when a derived address differs
+// from the expected address
+var ErrAddressMismatch = errors.New("address mismatch")
+
+// ErrMissingScriptType is returned when config does not specify
+// an addr type
+var ErrMissingScriptType = errors.New("missing addr type")
+
+// ErrNetworkMismatch is returned when input belongs to a network
+// other than the expected network
+var ErrNetworkMismatch = errors.New("network mismatch")
+
+// ErrAddressChecksum is returned when checksum of an otherwise
+// well formed address does not match
+var ErrAddressChecksum = errors.New("address checksum failed")
+
+// ErrUnsupportedDescriptor is returned when an output descriptor is not
+// a single key descriptor, such as a multisig descriptor
+var ErrUnsupportedDescriptor = errors.New("unsupported descriptor")
+
+// ErrMissingSeed is returned when neither seed nor seed hex is provided
+var ErrMissingSeed = errors.New("missing seed")
+
+// ErrInvalidSeedLength is returned when a seed is not 16 to 64 bytes
+// long as required by BIP-32
+var ErrInvalidSeedLength = errors.New("invalid seed length")
+
+// ErrWeakSeed is returned when a seed is all zero or trivially
+// repetitive. Keys derived from such seeds are easily guessed.
+var ErrWeakSeed = errors.New("weak seed, set AllowWeakSeed to derive keys from it")
+
+// ErrRangeTooLarge is returned when a range of child keys exceeds
+// MaxRangeCount or runs past the end of its index space
+var ErrRangeTooLarge = errors.New("range too large")
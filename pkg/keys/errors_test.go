@@ -0,0 +1,36 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestBatchError_Is(t *testing.T) {
+	_, err := DeriveMany(testZpubAbandonAbout, []string{"m/0/0", "m/0h/0"})
+	if err == nil {
+		t.Fatal("expected error deriving hardened child of xpub")
+	}
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errs) != 1 || batchErr.Total != 2 {
+		t.Fatal("expected batch error with 1 of 2 failed, got", err)
+	}
+
+	if !errors.Is(err, ErrHardenedFromPublic) {
+		t.Fatal("expected batch error to match ErrHardenedFromPublic, got", err)
+	}
+
+	if errors.Is(err, ErrRangeTooLarge) {
+		t.Fatal("expected batch error not to match ErrRangeTooLarge")
+	}
+
+	_, parseErr := strconv.ParseUint("x", 10, 32)
+	err = &BatchError{Errs: []error{ErrWeakSeed, fmt.Errorf("failed to parse: %w", parseErr)}, Total: 3}
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || numErr.Num != "x" {
+		t.Fatal("expected errors.As to find aggregated *strconv.NumError, got", err)
+	}
+}
@@ -3,19 +3,23 @@ package keys
 import (
 	"bufio"
 	"bytes"
-	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"path"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/tyler-smith/go-bip32"
 )
 
@@ -34,6 +38,30 @@ var netParams = map[string]*chaincfg.Params{
 	NetworkTypeTestnet: &chaincfg.TestNet3Params,
 }
 
+// zecAddrPrefixes are two byte Zcash transparent p2pkh address prefixes,
+// which cannot be represented by chaincfg.Params since it only allows
+// a single byte address id.
+// https://github.com/zcash/zcash/blob/master/src/chainparams.cpp
+var zecAddrPrefixes = map[string][]byte{
+	NetworkTypeMainnet: {0x1c, 0xb8}, // t1
+	NetworkTypeTestnet: {0x1d, 0x25}, // tm
+}
+
+// segWitCoinTypes lists whether coin types support segwit addresses
+var segWitCoinTypes = map[string]bool{
+	CoinTypeBtc: true,
+	CoinTypeZec: false,
+}
+
+// supportsSegWit checks if segwit addresses can be generated for the coin
+// type and network params
+func supportsSegWit(coinType string, params *chaincfg.Params) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return segWitCoinTypes[coinType] && len(params.Bech32HRPSegwit) > 0
+}
+
 var (
 	keyVersions       map[string][]byte
 	mainnetVersions   map[string]struct{}
@@ -73,6 +101,13 @@ func init() {
 		path.Join(CoinTypeBtc, NetworkTypeMainnet, AddrTypeP2wsh, KeyTypePrv):       mustDecodeHex(Zprv),
 		path.Join(CoinTypeBtc, NetworkTypeTestnet, AddrTypeP2wsh, KeyTypePub):       mustDecodeHex(Vpub),
 		path.Join(CoinTypeBtc, NetworkTypeTestnet, AddrTypeP2wsh, KeyTypePrv):       mustDecodeHex(Vprv),
+
+		// zcash transparent addresses only support p2pkh and use bitcoin
+		// extended key versions
+		path.Join(CoinTypeZec, NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(xpub),
+		path.Join(CoinTypeZec, NetworkTypeMainnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(xprv),
+		path.Join(CoinTypeZec, NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePub): mustDecodeHex(tpub),
+		path.Join(CoinTypeZec, NetworkTypeTestnet, AddrTypeP2pkhOrP2sh, KeyTypePrv): mustDecodeHex(tprv),
 	}
 
 	mainnetVersions = map[string]struct{}{
@@ -150,6 +185,65 @@ func init() {
 		Vpub: AddrTypeP2wsh,
 		Vprv: AddrTypeP2wsh,
 	}
+
+	sortKeyVersions()
+	registerBuiltinCoins()
+}
+
+// keyVersionEntry is a key version along with the coin type, network,
+// addr type and key type it is registered for
+type keyVersionEntry struct {
+	coinType string
+	network  string
+	addrType string
+	keyType  string
+	version  []byte
+}
+
+// orderedKeyVersions lists entries of keyVersions ordered by coin type,
+// with btc first, followed by network, addr type and key type, allowing
+// deterministic iteration over key versions
+var orderedKeyVersions []keyVersionEntry
+
+// sortKeyVersions rebuilds orderedKeyVersions from keyVersions and is
+// called whenever key versions are registered with registryMu held
+func sortKeyVersions() {
+	coinTypeOrder := make(map[string]int)
+	for i, coinType := range sortedCoinTypes() {
+		coinTypeOrder[coinType] = i
+	}
+
+	entries := make([]keyVersionEntry, 0, len(keyVersions))
+	for k, version := range keyVersions {
+		parts := strings.Split(k, "/")
+		if len(parts) != 4 {
+			continue
+		}
+
+		entries = append(entries, keyVersionEntry{
+			coinType: parts[0],
+			network:  parts[1],
+			addrType: parts[2],
+			keyType:  parts[3],
+			version:  version,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.coinType != b.coinType {
+			return coinTypeOrder[a.coinType] < coinTypeOrder[b.coinType]
+		}
+		if a.network != b.network {
+			return a.network < b.network
+		}
+		if a.addrType != b.addrType {
+			return a.addrType < b.addrType
+		}
+		return a.keyType < b.keyType
+	})
+
+	orderedKeyVersions = entries
 }
 
 // IsValidBase58String checks if all chars in input string
@@ -179,10 +273,94 @@ type Key struct {
 	Addr           string `json:"addr,omitempty" yaml:"addr,omitempty"`
 	AddrType       string `json:"addrType,omitempty" yaml:"addrType,omitempty"`
 	DerivationPath string `json:"derivationPath,omitempty" yaml:"derivationPath,omitempty"`
-	CoinType       string `json:"coinType,omitempty" yaml:"coinType,omitempty"`
-	Network        string `json:"network,omitempty" yaml:"network,omitempty"`
-	segWitNested   string
-	segWitBech32   string
+	// PrimaryAddress is the address matching the script type of the key,
+	// i.e., legacy for xpub, segwit-compatible for ypub and bech32 for zpub
+	PrimaryAddress string `json:"primaryAddress,omitempty" yaml:"primaryAddress,omitempty"`
+	// ResolvedIndices are the child indices that were applied to derive
+	// this key, with hardened indices offset by bip32.FirstHardenedChild
+	ResolvedIndices []uint32 `json:"resolvedIndices,omitempty" yaml:"resolvedIndices,omitempty"`
+	CoinType        string   `json:"coinType,omitempty" yaml:"coinType,omitempty"`
+	Network         string   `json:"network,omitempty" yaml:"network,omitempty"`
+	// IsPrivate is true when the key carries private key material
+	IsPrivate bool `json:"isPrivate,omitempty" yaml:"isPrivate,omitempty"`
+	// IsCompressed is true when the pub key is serialized in 33 byte
+	// compressed form instead of 65 byte uncompressed form
+	IsCompressed bool `json:"isCompressed,omitempty" yaml:"isCompressed,omitempty"`
+	// IsHardened is true when the last child index applied to derive
+	// the key is hardened
+	IsHardened   bool `json:"isHardened,omitempty" yaml:"isHardened,omitempty"`
+	segWitNested string
+	segWitBech32 string
+	// params are network params used to derive the key, including
+	// config overrides, if any
+	params *chaincfg.Params
+}
+
+// chainParams returns network params used to derive the key, or default
+// network params of its coin type and network when they are not known,
+// such as for keys decoded from binary form
+func (k *Key) chainParams() *chaincfg.Params {
+	if k.params != nil {
+		return k.params
+	}
+
+	coinType := k.CoinType
+	if len(coinType) == 0 {
+		coinType = CoinTypeBtc
+	}
+
+	return networkParams(coinType, normalizeNetwork(k.Network))
+}
+
+// MinimalKey carries only non-secret address data of a Key and
+// is suitable for serializing a large number of derived keys
+type MinimalKey struct {
+	DerivationPath string `json:"derivationPath,omitempty" yaml:"derivationPath,omitempty"`
+	Addr           string `json:"addr,omitempty" yaml:"addr,omitempty"`
+	PrimaryAddress string `json:"primaryAddress,omitempty" yaml:"primaryAddress,omitempty"`
+	PubKeyHex      string `json:"pubKeyHex,omitempty" yaml:"pubKeyHex,omitempty"`
+}
+
+// Minimal returns MinimalKey dropping seed, private keys and
+// other metadata of the key
+func (k *Key) Minimal() *MinimalKey {
+	return &MinimalKey{
+		DerivationPath: k.DerivationPath,
+		Addr:           k.Addr,
+		PrimaryAddress: k.PrimaryAddress,
+		PubKeyHex:      k.PubKeyHex,
+	}
+}
+
+// PubKeyBytes returns raw serialized pub key bytes, allowing
+// callers to encode pub key as needed
+func (k *Key) PubKeyBytes() ([]byte, error) {
+	if len(k.PubKeyHex) == 0 {
+		return nil, fmt.Errorf("key has no pub key")
+	}
+
+	b, err := hex.DecodeString(k.PubKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode pub key hex: %w", err)
+	}
+
+	return b, nil
+}
+
+// EqualKeyMaterial reports whether both keys carry the same extended pub key,
+// pub key and addresses, ignoring seed, derivation path, private key and
+// coin metadata, which depend on how the keys were obtained
+func (k *Key) EqualKeyMaterial(other *Key) bool {
+	if k == nil || other == nil {
+		return k == other
+	}
+
+	return k.XPub == other.XPub &&
+		strings.EqualFold(k.PubKeyHex, other.PubKeyHex) &&
+		k.Addr == other.Addr &&
+		k.PrimaryAddress == other.PrimaryAddress &&
+		k.segWitNested == other.segWitNested &&
+		k.segWitBech32 == other.segWitBech32
 }
 
 type Config struct {
@@ -190,24 +368,80 @@ type Config struct {
 	Network        string
 	DerivationPath string
 	AddrType       string
+	CoinType       string
+	// DerivationIndices is an alternative to DerivationPath and
+	// allows providing pre-parsed child indices. Hardened indices
+	// must already be offset by bip32.FirstHardenedChild.
+	// DerivationPath must be empty when DerivationIndices is set.
+	DerivationIndices []uint32
+	// Bech32HRP, address ids, wif private key id and extended key
+	// versions, when set, override corresponding network parameters
+	// allowing keys to be generated for bitcoin forks that are
+	// not explicitly supported. Zero values retain network defaults.
+	Bech32HRP        string
+	PubKeyHashAddrID byte
+	ScriptHashAddrID byte
+	PrivateKeyID     byte
+	PubKeyVersion    []byte
+	PrvKeyVersion    []byte
+	// EnforceBIP44Hardening, when set, rejects derivation paths with at
+	// least three levels that leave purpose, coin type or account level
+	// unhardened, such as m/84/0/0 instead of m/84h/0h/0h
+	EnforceBIP44Hardening bool
+	// EnforceCoinTypeIndex, when set, rejects derivation paths whose coin
+	// type level does not match SLIP-44 coin type of the coin, such as
+	// m/44h/0h/0h/0/0 for ltc
+	EnforceCoinTypeIndex bool
+	// SeedHex is a hex encoded seed and an alternative to Seed.
+	// Seed must be empty when SeedHex is set.
+	SeedHex string
+	// AllowWeakSeed, when set, allows deriving keys from seeds that
+	// fail the weak seed heuristic, such as all zero seeds
+	AllowWeakSeed bool
+	// AddressTypes, when set, limits address generation to the listed
+	// single key script types, such as p2wpkh, saving address construction
+	// of other script types when deriving many keys. Address of the key is
+	// left empty when its script type is not listed. By default, addresses
+	// of all single key script types are generated.
+	AddressTypes []string
 }
 
-// New generates a new key pair with a seed. The derivation paths
-// can be successive derivation indices such as m, 0, 0h etc.
-// or can be provided as m/0/0h.
-func New(config *Config) (*Key, error) {
-	seed, network, derivationPath, addrType :=
-		config.Seed,
-		strings.ToLower(config.Network),
+// derivationPlan is the validated and resolved input of key derivation
+type derivationPlan struct {
+	seed       []byte
+	indices    []uint32
+	prvVersion []byte
+	keyParams  *keyParams
+}
+
+// planDerivation validates config and resolves seed, derivation indices,
+// key versions and network params without deriving any keys
+func planDerivation(config *Config) (*derivationPlan, error) {
+	network, derivationPath, addrType, coinType :=
+		normalizeNetwork(config.Network),
 		strings.ToLower(config.DerivationPath),
-		strings.ToLower(config.AddrType)
+		strings.ToLower(config.AddrType),
+		strings.ToLower(config.CoinType)
 
-	switch network {
-	case NetworkTypeMainnet, NetworkTypeTestnet:
-	default:
-		return nil, fmt.Errorf("invalid or unsupported network: %s. allowed networks are %v", network,
-			[]string{NetworkTypeMainnet, NetworkTypeTestnet},
-		)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	seed, err := config.seed()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if err := checkSeedLength(seed); err != nil {
+		return nil, err
+	}
+
+	if !config.AllowWeakSeed && isWeakSeed(seed) {
+		return nil, ErrWeakSeed
+	}
+
+	if len(coinType) == 0 {
+		coinType = CoinTypeBtc
 	}
 
 	// when using BIP-32 address type, the default behavior of the
@@ -216,13 +450,31 @@ func New(config *Config) (*Key, error) {
 		derivationPath = "m/0/0"
 	}
 
-	switch addrType {
-	case AddrTypeLegacy, AddrTypeBip44, AddrTypeBip32:
-		addrType = AddrTypeP2pkhOrP2sh
-	case AddrTypeP2sh, AddrTypeSegWitCompatible, AddrTypeBip49:
-		addrType = AddrTypeP2wpkhP2sh
-	case AddrTypeSegWitNative, AddrTypeBech32, AddrTypeBip84:
-		addrType = AddrTypeP2wpkh
+	addrType = normalizeAddrType(addrType)
+
+	// coin type is 133h for ZEC mainnet and
+	// 1h for all testnets per
+	// https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+	if derivationPath == "auto" && coinType == CoinTypeZec {
+		switch network {
+		case NetworkTypeMainnet:
+			derivationPath = "m/44h/133h/0h/0/0"
+		case NetworkTypeTestnet:
+			derivationPath = "m/44h/1h/0h/0/0"
+		}
+	}
+
+	// coin type of registered coins is taken from their network params,
+	// such as 2h for LTC mainnet and 1h for all testnets
+	if coin, ok := LookupCoin(coinType); ok && derivationPath == "auto" {
+		switch addrType {
+		case AddrTypeP2pkhOrP2sh:
+			derivationPath = fmt.Sprintf("m/44h/%dh/0h/0/0", coin.Params[network].HDCoinType)
+		case AddrTypeP2wpkhP2sh, AddrTypeP2wshP2sh:
+			derivationPath = fmt.Sprintf("m/49h/%dh/0h/0/0", coin.Params[network].HDCoinType)
+		case AddrTypeP2wpkh, AddrTypeP2wsh:
+			derivationPath = fmt.Sprintf("m/84h/%dh/0h/0/0", coin.Params[network].HDCoinType)
+		}
 	}
 
 	// coin type is 0h for BTC mainnet and
@@ -238,6 +490,8 @@ func New(config *Config) (*Key, error) {
 				derivationPath = "m/49h/0h/0h/0/0"
 			case AddrTypeP2wpkh, AddrTypeP2wsh:
 				derivationPath = "m/84h/0h/0h/0/0"
+			case AddrTypeP2tr:
+				derivationPath = "m/86h/0h/0h/0/0"
 			}
 		case NetworkTypeTestnet:
 			switch addrType {
@@ -247,57 +501,476 @@ func New(config *Config) (*Key, error) {
 				derivationPath = "m/49h/1h/0h/0/0"
 			case AddrTypeP2wpkh, AddrTypeP2wsh:
 				derivationPath = "m/84h/1h/0h/0/0"
+			case AddrTypeP2tr:
+				derivationPath = "m/86h/1h/0h/0/0"
 			}
 		}
 	}
 
-	// setup key versions based on network
-	var ok bool
-	bip32.PublicWalletVersion, ok = keyVersions[path.Join(CoinTypeBtc, network, addrType, KeyTypePub)]
+	// resolve key versions based on network without modifying
+	// bip32 pkg global key versions, so that keys can be generated
+	// concurrently
+	pubVersion, ok := lookupKeyVersion(coinType, network, keyVersionAddrType(addrType), KeyTypePub)
 	if !ok {
 		return nil, fmt.Errorf("failed to get key version for pubic key")
 	}
 
-	bip32.PrivateWalletVersion, ok = keyVersions[path.Join(CoinTypeBtc, network, addrType, KeyTypePrv)]
+	prvVersion, ok := lookupKeyVersion(coinType, network, keyVersionAddrType(addrType), KeyTypePrv)
 	if !ok {
 		return nil, fmt.Errorf("failed to get key version for private key")
 	}
 
-	xKey, err := bip32.NewMasterKey(seed)
+	params, err := configParams(config, coinType, network)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network params override: %w", err)
+	}
+
+	if len(config.PubKeyVersion) > 0 {
+		pubVersion = config.PubKeyVersion
+	}
+
+	if len(config.PrvKeyVersion) > 0 {
+		prvVersion = config.PrvKeyVersion
+	}
+
+	indices := config.DerivationIndices
+	if len(indices) == 0 {
+		indices, err = parseDerivationPath(derivationPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse derivation path: %w", err)
+		}
+	}
+
+	addressTypes, err := normalizeAddressTypes(config.AddressTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &derivationPlan{
+		seed:       seed,
+		indices:    indices,
+		prvVersion: prvVersion,
+		keyParams: &keyParams{
+			coinType:     coinType,
+			network:      network,
+			addrType:     addrType,
+			pubVersion:   pubVersion,
+			params:       params,
+			addressTypes: addressTypes,
+		},
+	}, nil
+}
+
+// keyVersionAddrType returns the addr type whose extended key versions are
+// used for keys of an addr type. BIP-86 taproot keys use xpub key versions.
+func keyVersionAddrType(addrType string) string {
+	if addrType == AddrTypeP2tr {
+		return AddrTypeP2pkhOrP2sh
+	}
+
+	return addrType
+}
+
+// PlanDerivation runs all validation and derivation path resolution of New
+// for each config without deriving keys or computing addresses and returns
+// errors aligned by index with input configs, i.e., nil for each config
+// that New would accept. It allows fast pre-flight validation of batch jobs.
+func PlanDerivation(configs []*Config) []error {
+	errs := make([]error, len(configs))
+	for i, config := range configs {
+		_, errs[i] = planDerivation(config)
+	}
+
+	return errs
+}
+
+// New generates a new key pair with a seed. The derivation paths
+// can be successive derivation indices such as m, 0, 0h etc.
+// or can be provided as m/0/0h.
+func New(config *Config) (*Key, error) {
+	plan, err := planDerivation(config)
+	if err != nil {
+		return nil, err
+	}
+
+	xKey, err := bip32.NewMasterKey(plan.seed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate root key: %w", err)
 	}
+	xKey.Version = plan.prvVersion
 
-	xKey, err = extendedKeyToDerivedExtendedKey(xKey, derivationPath)
+	xKey, err = extendedKeyToIndexDerivedExtendedKey(xKey, plan.indices)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive extended key: %w", err)
 	}
 
-	key, err := extendedKeyToKey(xKey)
+	key, err := extendedKeyToKey(xKey, plan.keyParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert extended key for output: %w", err)
 	}
 
+	seed, indices, addrType := plan.seed, plan.indices, plan.keyParams.addrType
+
 	key.Seed = hex.EncodeToString(seed)
-	key.DerivationPath = derivationPath
+	key.DerivationPath = formatDerivationPath(indices)
+	key.ResolvedIndices = indices
 
 	switch addrType {
 	case AddrTypeP2pkhOrP2sh:
 		key.segWitNested, key.segWitBech32 = "", ""
 		key.AddrType = AddrTypeLegacy
-	case AddrTypeP2wpkhP2sh, AddrTypeP2wshP2sh:
+	case AddrTypeP2wpkhP2sh:
 		key.Addr, key.segWitNested, key.segWitBech32 = key.segWitNested, "", ""
 		key.AddrType = fmt.Sprintf("%s, %s", AddrTypeSegWitCompatible, AddrTypeP2sh)
-	case AddrTypeP2wpkh, AddrTypeP2wsh:
+	case AddrTypeP2wpkh:
 		key.Addr, key.segWitNested, key.segWitBech32 = key.segWitBech32, "", ""
 		key.AddrType = fmt.Sprintf("%s, %s", AddrTypeSegWitNative, AddrTypeBech32)
+	case AddrTypeP2tr:
+		pubKeyBytes, err := key.PubKeyBytes()
+		if err != nil {
+			return nil, err
+		}
+		key.Addr, err = scriptTypeAddress(pubKeyBytes, AddrTypeP2tr, plan.keyParams.params)
+		if err != nil {
+			return nil, err
+		}
+		key.segWitNested, key.segWitBech32 = "", ""
+		key.AddrType = fmt.Sprintf("%s, %s", AddrTypeTaproot, AddrTypeP2tr)
+	case AddrTypeP2wshP2sh, AddrTypeP2wsh:
+		// multisig script types have no single key address
+		key.AddrType = addrType
 	default:
 		return nil, fmt.Errorf("invalid addr type")
 	}
+	key.PrimaryAddress = key.Addr
 
 	return key, nil
 }
 
+// MasterKey generates the depth-0 master key from a seed with key versions
+// resolved for the network and script type. Derivation path of the
+// returned key is m.
+func MasterKey(seed []byte, network, scriptType string) (*Key, error) {
+	return New(
+		&Config{
+			Seed:           seed,
+			Network:        network,
+			DerivationPath: "m",
+			AddrType:       scriptType,
+		},
+	)
+}
+
+// VerifyDerivation derives a key from seed at the derivation path and returns
+// an error wrapping ErrAddressMismatch if the derived address differs from the
+// expected address
+func VerifyDerivation(seed []byte, path, network, scriptType, expectedAddress string) error {
+	key, err := New(
+		&Config{
+			Seed:           seed,
+			Network:        network,
+			DerivationPath: path,
+			AddrType:       scriptType,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	if key.Addr != expectedAddress {
+		return fmt.Errorf("%w at %s for %s on %s: expected %s, derived %s",
+			ErrAddressMismatch, key.DerivationPath, scriptType, network, expectedAddress, key.Addr)
+	}
+
+	return nil
+}
+
+// RawDerive performs plain BIP-32 derivation of the seed at the derivation
+// path and returns the derived private key and chain code without any coin,
+// network or address assumptions, allowing keys to be derived for coins that
+// are not supported, such as at m/44h/<coin>h/0h/0/0
+func RawDerive(seed []byte, path string) (*btcec.PrivateKey, []byte, error) {
+	indices, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+
+	xKey, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate root key: %w", err)
+	}
+
+	xKey, err = extendedKeyToIndexDerivedExtendedKey(xKey, indices)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to derive extended key: %w", err)
+	}
+
+	prvKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), xKey.Key)
+
+	return prvKey, append([]byte{}, xKey.ChainCode...), nil
+}
+
+// Validate checks config for unsupported network, coin type and
+// addr type combinations and for unparsable derivation paths,
+// returning the first problem found
+func (c *Config) Validate() error {
+	network, derivationPath, addrType, coinType :=
+		normalizeNetwork(c.Network),
+		strings.ToLower(c.DerivationPath),
+		normalizeAddrType(strings.ToLower(c.AddrType)),
+		strings.ToLower(c.CoinType)
+
+	if len(coinType) == 0 {
+		coinType = CoinTypeBtc
+	}
+
+	switch coinType {
+	case CoinTypeBtc, CoinTypeZec:
+	default:
+		if _, ok := LookupCoin(coinType); !ok {
+			return fmt.Errorf("invalid or unsupported coin type: %s. allowed coin types are %v", coinType,
+				coinTypes(),
+			)
+		}
+	}
+
+	switch network {
+	case NetworkTypeMainnet, NetworkTypeTestnet:
+	default:
+		return fmt.Errorf("invalid or unsupported network: %s. allowed networks are %v", network,
+			[]string{NetworkTypeMainnet, NetworkTypeTestnet},
+		)
+	}
+
+	if len(addrType) == 0 {
+		return fmt.Errorf("%w, allowed addr types are %v", ErrMissingScriptType, SupportedScriptTypes())
+	}
+
+	if _, ok := lookupKeyVersion(coinType, network, keyVersionAddrType(addrType), KeyTypePub); !ok ||
+		(addrType == AddrTypeP2tr && coinType != CoinTypeBtc) {
+		return fmt.Errorf("invalid or unsupported addr type %s for coin type %s on %s", c.AddrType, coinType, network)
+	}
+
+	if networkParams(coinType, network) == nil {
+		return fmt.Errorf("%w for coin type %s on %s", ErrMissingNetworkParams, coinType, network)
+	}
+
+	params, err := configParams(c, coinType, network)
+	if err != nil {
+		return fmt.Errorf("invalid network params override: %w", err)
+	}
+
+	if params != networkParams(coinType, network) && coinType == CoinTypeZec {
+		return fmt.Errorf("network params override is not supported for coin type %s", coinType)
+	}
+
+	if !supportsSegWit(coinType, params) && addrType != AddrTypeP2pkhOrP2sh {
+		return fmt.Errorf("coin type %s on %s only supports %s addr type", coinType, network, AddrTypeLegacy)
+	}
+
+	if _, err := normalizeAddressTypes(c.AddressTypes); err != nil {
+		return err
+	}
+
+	indices := c.DerivationIndices
+	if len(indices) > 0 {
+		if len(derivationPath) > 0 {
+			return fmt.Errorf("derivation path and derivation indices are mutually exclusive, only one can be set")
+		}
+	} else if derivationPath != "auto" {
+		indices, err = parseDerivationPath(derivationPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse derivation path: %w", err)
+		}
+	}
+
+	if c.EnforceBIP44Hardening {
+		if err := checkBIP44Hardening(indices); err != nil {
+			return err
+		}
+	}
+
+	if c.EnforceCoinTypeIndex {
+		if err := checkCoinTypeIndex(indices, coinType, network); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.seed(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// min and max seed length in bytes per BIP-32
+const (
+	minSeedLength = 16
+	maxSeedLength = 64
+)
+
+// checkSeedLength checks that seed is present and is 16 to 64 bytes
+// long per BIP-32, regardless of whether weak seeds are allowed
+func checkSeedLength(seed []byte) error {
+	if len(seed) == 0 {
+		return ErrMissingSeed
+	}
+
+	if len(seed) < minSeedLength || len(seed) > maxSeedLength {
+		return fmt.Errorf("%w %d bytes, must be %d to %d bytes",
+			ErrInvalidSeedLength, len(seed), minSeedLength, maxSeedLength)
+	}
+
+	return nil
+}
+
+// minSeedDistinctBytes is the min number of distinct byte values
+// of a seed that is not considered weak
+const minSeedDistinctBytes = 4
+
+// isWeakSeed reports whether seed consists of fewer than
+// minSeedDistinctBytes distinct byte values, such as an all zero seed,
+// or repeats a pattern of up to 8 bytes, such as deadbeefdeadbeef...
+func isWeakSeed(seed []byte) bool {
+	distinct := make(map[byte]struct{})
+	for _, b := range seed {
+		distinct[b] = struct{}{}
+	}
+
+	if len(distinct) < minSeedDistinctBytes {
+		return true
+	}
+
+	for period := 1; period <= 8 && period < len(seed); period++ {
+		repeated := true
+		for i := period; i < len(seed); i++ {
+			if seed[i] != seed[i-period] {
+				repeated = false
+				break
+			}
+		}
+
+		if repeated {
+			return true
+		}
+	}
+
+	return false
+}
+
+// seed returns Seed or SeedHex decoded as seed bytes
+func (c *Config) seed() ([]byte, error) {
+	if len(c.SeedHex) == 0 {
+		return c.Seed, nil
+	}
+
+	if len(c.Seed) > 0 {
+		return nil, fmt.Errorf("seed and seed hex cannot both be set")
+	}
+
+	seed, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(c.SeedHex), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode seed hex: %w", err)
+	}
+
+	return seed, nil
+}
+
+// checkCoinTypeIndex checks that coin type level of a derivation path with
+// at least two levels matches SLIP-44 coin type of the coin, which is 1h for
+// all testnets
+// https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+func checkCoinTypeIndex(indices []uint32, coinType, network string) error {
+	if len(indices) < 2 {
+		return nil
+	}
+
+	var expected uint32
+	switch {
+	case network == NetworkTypeTestnet:
+		expected = 1
+	case coinType == CoinTypeZec:
+		expected = 133
+	default:
+		expected = networkParams(coinType, network).HDCoinType
+	}
+
+	if indices[1]%bip32.FirstHardenedChild != expected {
+		return fmt.Errorf("coin type level index %d of derivation path %s does not match %d of coin type %s on %s",
+			indices[1]%bip32.FirstHardenedChild, formatDerivationPath(indices), expected, coinType, network)
+	}
+
+	return nil
+}
+
+// checkBIP44Hardening checks that purpose, coin type and account levels
+// of a derivation path with at least three levels are hardened per BIP-44
+func checkBIP44Hardening(indices []uint32) error {
+	if len(indices) < 3 {
+		return nil
+	}
+
+	for i, level := range []string{"purpose", "coin type", "account"} {
+		if indices[i] < bip32.FirstHardenedChild {
+			return fmt.Errorf("%s level index %d of derivation path %s must be hardened",
+				level, indices[i], formatDerivationPath(indices))
+		}
+	}
+
+	return nil
+}
+
+// configParams returns network params with config overrides applied
+// to a copy of the network params
+func configParams(config *Config, coinType, network string) (*chaincfg.Params, error) {
+	for _, version := range [][]byte{config.PubKeyVersion, config.PrvKeyVersion} {
+		if len(version) > 0 && len(version) != 4 {
+			return nil, fmt.Errorf("extended key version must be 4 bytes long, found %d bytes", len(version))
+		}
+	}
+
+	params := networkParams(coinType, network)
+	if len(config.Bech32HRP) == 0 &&
+		config.PubKeyHashAddrID == 0 &&
+		config.ScriptHashAddrID == 0 &&
+		config.PrivateKeyID == 0 {
+		return params, nil
+	}
+
+	p := *params
+
+	// forks with overridden address ids but without a bech32 hrp are
+	// treated as not supporting segwit, since a bech32 address with
+	// network default hrp would belong to a different coin
+	p.Bech32HRPSegwit = ""
+	if len(config.Bech32HRP) > 0 {
+		// https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki#bech32
+		if len(config.Bech32HRP) > 83 {
+			return nil, fmt.Errorf("bech32 hrp must be 1 to 83 chars long")
+		}
+		for _, r := range config.Bech32HRP {
+			if r < 33 || r > 126 || (r >= 'A' && r <= 'Z') {
+				return nil, fmt.Errorf("invalid char %q in bech32 hrp, only lowercase us-ascii chars are allowed", r)
+			}
+		}
+		p.Bech32HRPSegwit = config.Bech32HRP
+	}
+
+	if config.PubKeyHashAddrID != 0 {
+		p.PubKeyHashAddrID = config.PubKeyHashAddrID
+	}
+
+	if config.ScriptHashAddrID != 0 {
+		p.ScriptHashAddrID = config.ScriptHashAddrID
+	}
+
+	if config.PrivateKeyID != 0 {
+		p.PrivateKeyID = config.PrivateKeyID
+	}
+
+	return &p, nil
+}
+
 func Prompt(w io.Writer) error {
 	if _, err := fmt.Fprintf(w, "Enter key: "); err != nil {
 		return fmt.Errorf("failed to write to output: %w", err)
@@ -306,18 +979,187 @@ func Prompt(w io.Writer) error {
 	return nil
 }
 
-func Read(r io.Reader) (string, error) {
-	inputReader := bufio.NewReader(r)
-	key, err := inputReader.ReadString('\n')
+// Read reads a line of input as a key, trimming surrounding whitespace
+// and byte order mark
+func Read(r io.Reader, opts ...ReadOption) (string, error) {
+	options := newReadOptions(opts)
+	if options.maxInputLength < 1 {
+		return "", fmt.Errorf("invalid max input length %d, must be positive", options.maxInputLength)
+	}
+
+	key, err := readLine(bufio.NewReaderSize(r, options.maxInputLength+2), options.maxInputLength)
 	if err != nil {
 		return "", fmt.Errorf("failed to read key from input: %w", err)
 	}
-	key = strings.Trim(key, "\n")
 
-	return key, nil
+	return tidyInput(key), nil
+}
+
+// MaxInputLength is the max length of a key or line of input accepted by
+// read and decode functions, guarding against unbounded memory use
+const MaxInputLength = 4096
+
+// ReadOption configures reading input via Read and ReadAll
+type ReadOption func(opts *readOptions)
+
+// readOptions are resolved options of reading input
+type readOptions struct {
+	maxInputLength int
+}
+
+// WithMaxInputLength overrides MaxInputLength as the max length of
+// a line of input
+func WithMaxInputLength(maxInputLength int) ReadOption {
+	return func(opts *readOptions) {
+		opts.maxInputLength = maxInputLength
+	}
+}
+
+// newReadOptions applies read options over defaults
+func newReadOptions(opts []ReadOption) *readOptions {
+	options := &readOptions{maxInputLength: MaxInputLength}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// checkInputLength returns ErrInputTooLong if input exceeds MaxInputLength
+func checkInputLength(input string) error {
+	if len(input) > MaxInputLength {
+		return fmt.Errorf("%w, found %d bytes, max allowed is %d", ErrInputTooLong, len(input), MaxInputLength)
+	}
+
+	return nil
+}
+
+// readLine reads a line of input without buffering lines longer than
+// the buffer size of the reader, which should be sized to accommodate
+// maxInputLength and line terminators
+func readLine(inputReader *bufio.Reader, maxInputLength int) (string, error) {
+	line, err := inputReader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", fmt.Errorf("%w, max allowed is %d", ErrInputTooLong, maxInputLength)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	input := string(line)
+	if n := len(strings.TrimRight(input, "\r\n")); n > maxInputLength {
+		return "", fmt.Errorf("%w, found %d bytes, max allowed is %d", ErrInputTooLong, n, maxInputLength)
+	}
+
+	return input, nil
 }
 
+// ReadAll reads all non-empty lines from input skipping lines
+// beginning with #, which are treated as comments
+func ReadAll(r io.Reader, opts ...ReadOption) ([]string, error) {
+	options := newReadOptions(opts)
+	if options.maxInputLength < 1 {
+		return nil, fmt.Errorf("invalid max input length %d, must be positive", options.maxInputLength)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, options.maxInputLength+2), options.maxInputLength+2)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if n := len(scanner.Text()); n > options.maxInputLength {
+			return nil, fmt.Errorf("failed to read input at line %d: %w, found %d bytes, max allowed is %d",
+				lineNumber, ErrInputTooLong, n, options.maxInputLength)
+		}
+
+		line := tidyInput(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			err = fmt.Errorf("%w, max allowed is %d", ErrInputTooLong, options.maxInputLength)
+		}
+		return nil, fmt.Errorf("failed to read input at line %d: %w", lineNumber+1, err)
+	}
+
+	return lines, nil
+}
+
+// DefaultReadAttempts is a sensible number of times for ReadValidated
+// to prompt for input before giving up
+const DefaultReadAttempts = 3
+
+// ReadValidated prompts for and reads a key from input, validating it with
+// validate and re-prompting with the validation error on invalid input, up
+// to maxAttempts times
+func ReadValidated(r io.Reader, w io.Writer, validate func(string) error, maxAttempts int) (string, error) {
+	if maxAttempts < 1 {
+		return "", fmt.Errorf("invalid max attempts %d, must be positive", maxAttempts)
+	}
+
+	inputReader := bufio.NewReaderSize(r, MaxInputLength+2)
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := Prompt(w); err != nil {
+			return "", err
+		}
+
+		var input string
+		input, err = readLine(inputReader, MaxInputLength)
+		if err != nil {
+			return "", fmt.Errorf("failed to read key from input: %w", err)
+		}
+
+		input = tidyInput(input)
+		if err = validate(input); err == nil {
+			return input, nil
+		}
+
+		if _, err := fmt.Fprintf(w, "invalid input: %v\n", err); err != nil {
+			return "", fmt.Errorf("failed to write to output: %w", err)
+		}
+	}
+
+	return "", fmt.Errorf("failed to read valid input after %d attempts: %w", maxAttempts, err)
+}
+
+// tidyInput strips windows line endings, utf-8 byte order mark and
+// surrounding whitespace, which otherwise corrupt key parsing
+func tidyInput(input string) string {
+	input = strings.TrimSpace(input)
+	input = strings.TrimPrefix(input, utf8BOM)
+	return strings.TrimSpace(input)
+}
+
+// DecodePublicHex decodes a hex encoded pub key and generates its
+// mainnet address
 func DecodePublicHex(keyString string) (*Key, error) {
+	return DecodePublicHexWithNetwork(keyString, NetworkTypeMainnet)
+}
+
+// DecodePublicHexWithNetwork decodes a hex encoded compressed or
+// uncompressed pub key and generates its p2pkh address for the network
+// from the serialization of the input
+func DecodePublicHexWithNetwork(keyString, network string) (*Key, error) {
+	if err := checkInputLength(keyString); err != nil {
+		return nil, err
+	}
+
+	network = normalizeNetwork(network)
+	params, ok := netParams[network]
+	if !ok {
+		return nil, fmt.Errorf("invalid or unsupported network: %s. allowed networks are %v", network,
+			[]string{NetworkTypeMainnet, NetworkTypeTestnet},
+		)
+	}
+
 	pubKeyBytes, err := hex.DecodeString(keyString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode pub key: %w", err)
@@ -328,7 +1170,15 @@ func DecodePublicHex(keyString string) (*Key, error) {
 		return nil, fmt.Errorf("failed to parse pub key: %w", err)
 	}
 
-	addressPubKey, err := btcutil.NewAddressPubKey(pub.SerializeCompressed(), netParams[NetworkTypeMainnet])
+	// address is generated from the serialization of the input, since
+	// compressed and uncompressed pub keys have different addresses
+	isCompressed := len(pubKeyBytes) == btcec.PubKeyBytesLenCompressed
+	serializedPubKey := pub.SerializeCompressed()
+	if !isCompressed {
+		serializedPubKey = pub.SerializeUncompressed()
+	}
+
+	addressPubKey, err := btcutil.NewAddressPubKey(serializedPubKey, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate new address from pub key: %w", err)
 	}
@@ -336,33 +1186,55 @@ func DecodePublicHex(keyString string) (*Key, error) {
 	addr := addressPubKey.EncodeAddress()
 
 	key := &Key{
-		XPrv:      "",
-		XPub:      "",
-		PrvKeyWif: "",
-		PubKeyHex: keyString,
-		Addr:      addr,
-		Network:   NetworkTypeMainnet,
-		CoinType:  CoinTypeBtc,
+		XPrv:           "",
+		XPub:           "",
+		PrvKeyWif:      "",
+		PubKeyHex:      keyString,
+		Addr:           addr,
+		PrimaryAddress: addr,
+		Network:        network,
+		CoinType:       CoinTypeBtc,
+		IsPrivate:      false,
+		IsCompressed:   isCompressed,
 	}
 
 	return key, nil
 }
 
+// DecodePublicHexMulti decodes a hex encoded pub key and generates a Key
+// for each of the networks, keyed by network. Mainnet and testnet keys are
+// generated when no networks are provided.
+func DecodePublicHexMulti(keyString string, networks []string) (map[string]*Key, error) {
+	if len(networks) == 0 {
+		networks = []string{NetworkTypeMainnet, NetworkTypeTestnet}
+	}
+
+	keys := make(map[string]*Key, len(networks))
+	for _, network := range networks {
+		key, err := DecodePublicHexWithNetwork(keyString, network)
+		if err != nil {
+			return nil, err
+		}
+		keys[key.Network] = key
+	}
+
+	return keys, nil
+}
+
+// DecodePrivateWifKey decodes a wif formatted private key. Testnet and
+// regtest keys share the wif private key id and are reported as testnet.
 func DecodePrivateWifKey(keyString string) (*Key, error) {
+	if err := checkInputLength(keyString); err != nil {
+		return nil, err
+	}
+
 	wif, err := btcutil.DecodeWIF(keyString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode wif: %w", err)
 	}
 
-	var network string
-	for k, v := range netParams {
-		if wif.IsForNet(v) {
-			network = k
-			break
-		}
-	}
-
-	if len(network) == 0 {
+	network, _, ok := wifNetwork(wif)
+	if !ok {
 		return nil, fmt.Errorf("detected network is not supported, only btc mainnet and testnet keys are supported")
 	}
 
@@ -375,13 +1247,16 @@ func DecodePrivateWifKey(keyString string) (*Key, error) {
 	addr := addressPubKey.EncodeAddress()
 
 	key := &Key{
-		XPrv:      "",
-		XPub:      "",
-		PrvKeyWif: keyString,
-		PubKeyHex: hex.EncodeToString(serializedPubKey),
-		Addr:      addr,
-		Network:   network,
-		CoinType:  CoinTypeBtc,
+		XPrv:           "",
+		XPub:           "",
+		PrvKeyWif:      keyString,
+		PubKeyHex:      hex.EncodeToString(serializedPubKey),
+		Addr:           addr,
+		PrimaryAddress: addr,
+		Network:        network,
+		CoinType:       CoinTypeBtc,
+		IsPrivate:      true,
+		IsCompressed:   wif.CompressPubKey,
 	}
 
 	return key, nil
@@ -396,52 +1271,156 @@ func DecodeExtendedKey(keyString string) (*Key, error) {
 	return key, nil
 }
 
-func Derive(keyString string, derivationPath string) (*Key, error) {
+// Derive derives key at the derivation path of the extended key. Keys of
+// coins sharing key versions with btc, such as a zec xprv or an ltc zprv,
+// are derived as btc keys unless the coin type is set via WithCoinType.
+func Derive(keyString string, derivationPath string, opts ...DeriveOption) (*Key, error) {
+	indices, err := parseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+
+	return DeriveIndices(keyString, indices, opts...)
+}
+
+// DeriveIndices derives key by applying child indices to the extended key.
+// Hardened indices must already be offset by bip32.FirstHardenedChild.
+func DeriveIndices(keyString string, indices []uint32, opts ...DeriveOption) (*Key, error) {
+	options := newDeriveOptions(opts)
+
+	bip32Key, err := deserializeExtendedKey(keyString)
+	if err != nil {
+		return nil, err
+	}
+
+	bip32Key, err = extendedKeyToIndexDerivedExtendedKey(bip32Key, indices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive extended key: %w", err)
+	}
+
+	key, err := derivedExtendedKeyToKey(bip32Key, options.coinType)
+	if err != nil {
+		return nil, err
+	}
+
+	key.ResolvedIndices = append([]uint32{}, indices...)
+
+	return key, nil
+}
+
+// deserializeExtendedKey deserializes base58 encoded extended key and
+// verifies that the key version is known
+func deserializeExtendedKey(keyString string) (*bip32.Key, error) {
+	if err := checkInputLength(keyString); err != nil {
+		return nil, err
+	}
+
 	bip32Key, err := bip32.B58Deserialize(keyString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to deserialize key: %w", err)
 	}
 
-	versions, ok := versionToVersions[hex.EncodeToString(bip32Key.Version)]
-	if !ok {
-		return nil, fmt.Errorf("failed to identity valid key version: %w", err)
+	if _, ok := lookupVersions(bip32Key.Version); !ok {
+		return nil, fmt.Errorf("failed to identity valid key version")
+	}
+
+	if err := ValidateKeyPrefix(bip32Key.IsPrivate, base58.Decode(keyString)[45]); err != nil {
+		return nil, err
 	}
 
-	bip32.PublicWalletVersion = mustDecodeHex(versions[0])
-	bip32.PrivateWalletVersion = mustDecodeHex(versions[1])
+	return bip32Key, nil
+}
 
-	bip32Key, err = extendedKeyToDerivedExtendedKey(bip32Key, derivationPath)
+// ChainCode deserializes an extended key and returns its 32 byte chain code
+func ChainCode(keyString string) ([]byte, error) {
+	bip32Key, err := deserializeExtendedKey(keyString)
 	if err != nil {
-		return nil, fmt.Errorf("failed to derive extended key: %w", err)
+		return nil, err
 	}
 
-	key, err := extendedKeyToKey(bip32Key)
+	return append([]byte{}, bip32Key.ChainCode...), nil
+}
+
+// PrivKey deserializes a private extended key and returns its
+// private key as btcec private key
+func PrivKey(keyString string) (*btcec.PrivateKey, error) {
+	bip32Key, err := deserializeExtendedKey(keyString)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bip32Key.IsPrivate {
+		return nil, fmt.Errorf("extended key is not a private key")
+	}
+
+	prv, _ := btcec.PrivKeyFromBytes(btcec.S256(), bip32Key.Key)
+
+	return prv, nil
+}
+
+// PubKey deserializes a public or private extended key and returns
+// its public key as btcec public key
+func PubKey(keyString string) (*btcec.PublicKey, error) {
+	bip32Key, err := deserializeExtendedKey(keyString)
+	if err != nil {
+		return nil, err
+	}
+
+	if bip32Key.IsPrivate {
+		_, pub := btcec.PrivKeyFromBytes(btcec.S256(), bip32Key.Key)
+		return pub, nil
+	}
+
+	pub, err := btcec.ParsePubKey(bip32Key.Key, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pub key: %w", err)
+	}
+
+	return pub, nil
+}
+
+// derivedExtendedKeyToKey converts extended key to Key retaining
+// only the address corresponding to the key version. Coin type may be
+// empty, see versionKeyParams for how it is resolved.
+func derivedExtendedKeyToKey(bip32Key *bip32.Key, coinType string) (*Key, error) {
+	kp, err := versionKeyParams(bip32Key.Version, coinType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve key params: %w", err)
+	}
+
+	key, err := extendedKeyToKey(bip32Key, kp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get key from extended key")
 	}
 
-	switch versionToAddrType[hex.EncodeToString(bip32Key.Version)] {
+	switch kp.addrType {
 	case AddrTypeP2pkhOrP2sh:
 		key.segWitNested, key.segWitBech32 = "", ""
-	case AddrTypeP2wpkhP2sh, AddrTypeP2wshP2sh:
+	case AddrTypeP2wpkhP2sh:
 		key.Addr, key.segWitNested, key.segWitBech32 = key.segWitNested, "", ""
-	case AddrTypeP2wpkh, AddrTypeP2wsh:
+	case AddrTypeP2wpkh:
 		key.Addr, key.segWitNested, key.segWitBech32 = key.segWitBech32, "", ""
 	}
 
-	switch versionToAddrType[hex.EncodeToString(bip32Key.Version)] {
+	switch kp.addrType {
 	case AddrTypeP2pkhOrP2sh:
 		key.AddrType = AddrTypeLegacy
-	case AddrTypeP2wpkhP2sh, AddrTypeP2wshP2sh:
+	case AddrTypeP2wpkhP2sh:
 		key.AddrType = fmt.Sprintf("%s, %s", AddrTypeSegWitCompatible, AddrTypeP2sh)
-	case AddrTypeP2wpkh, AddrTypeP2wsh:
+	case AddrTypeP2wpkh:
 		key.AddrType = fmt.Sprintf("%s, %s", AddrTypeSegWitNative, AddrTypeBech32)
+	case AddrTypeP2wshP2sh, AddrTypeP2wsh:
+		// multisig script types have no single key address
+		key.AddrType = kp.addrType
 	}
+	key.PrimaryAddress = key.Addr
 
 	return key, nil
 }
 
-func extendedKeyToDerivedExtendedKey(key *bip32.Key, derivationPath string) (*bip32.Key, error) {
+// parseDerivationPath parses derivation path such as m/44h/0h/0h/0/0
+// into child indices with hardened indices offset by bip32.FirstHardenedChild
+func parseDerivationPath(derivationPath string) ([]uint32, error) {
 	derivationPath = strings.Trim(strings.ToLower(derivationPath), "/")
 	if len(derivationPath) == 0 {
 		derivationPath = "m"
@@ -455,12 +1434,13 @@ func extendedKeyToDerivedExtendedKey(key *bip32.Key, derivationPath string) (*bi
 		return nil, fmt.Errorf("invalid derivation path, must start with m: %s", derivationPath)
 	}
 
+	indices := make([]uint32, 0, len(parts)-1)
 	for i, part := range parts {
 		if i == 0 {
 			continue
 		}
 		var idx uint32
-		if part[len(part)-1] == '\'' || part[len(part)-1] == 'h' {
+		if len(part) > 0 && (part[len(part)-1] == '\'' || part[len(part)-1] == 'h') {
 			idx = bip32.FirstHardenedChild
 			part = part[:len(part)-1]
 		}
@@ -471,34 +1451,176 @@ func extendedKeyToDerivedExtendedKey(key *bip32.Key, derivationPath string) (*bi
 		}
 
 		idx += uint32(index)
+		indices = append(indices, idx)
+	}
+
+	return indices, nil
+}
+
+// FormatDerivationPath formats child indices as a derivation path using
+// the hardened marker, i.e., as m/44h/0h/0h/0/0 or m/44'/0'/0'/0/0.
+// Markers other than HardenedMarkerApostrophe render as HardenedMarkerH.
+func FormatDerivationPath(indices []uint32, marker string) string {
+	if marker != HardenedMarkerApostrophe {
+		marker = HardenedMarkerH
+	}
+
+	parts := make([]string, 0, len(indices)+1)
+	parts = append(parts, "m")
+	for _, index := range indices {
+		if index >= bip32.FirstHardenedChild {
+			parts = append(parts, fmt.Sprintf("%d%s", index-bip32.FirstHardenedChild, marker))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d", index))
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// formatDerivationPath formats child indices as a derivation path
+// using HardenedMarkerH. Callers preferring another marker can format
+// resolved indices of a key via FormatDerivationPath.
+func formatDerivationPath(indices []uint32) string {
+	return FormatDerivationPath(indices, HardenedMarkerH)
+}
+
+// maxDepth is the max depth of an extended key per BIP-32
+const maxDepth = 255
+
+// extendedKeyToIndexDerivedExtendedKey derives successive child keys
+// for each of the input child indices. Derived key retains the version
+// of the input key instead of bip32 pkg global key versions.
+func extendedKeyToIndexDerivedExtendedKey(key *bip32.Key, indices []uint32) (*bip32.Key, error) {
+	if len(indices) == 0 {
+		return key, nil
+	}
+
+	// depth is serialized as a single byte
+	if int(key.Depth)+len(indices) > maxDepth {
+		return nil, fmt.Errorf("%w: key at depth %d cannot be derived %d levels deeper, max depth is %d",
+			ErrDepthOverflow, key.Depth, len(indices), maxDepth)
+	}
+
+	version := key.Version
+	var err error
+	for i, idx := range indices {
 		key, err = key.NewChildKey(idx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate %d child key: %w", i, err)
+			if errors.Is(err, bip32.ErrInvalidPrivateKey) || errors.Is(err, bip32.ErrInvalidPublicKey) {
+				err = fmt.Errorf("%w: %v", ErrInvalidChildKey, err)
+			}
+			if errors.Is(err, bip32.ErrHardnedChildPublicKey) {
+				err = ErrHardenedFromPublic
+			}
+			return nil, fmt.Errorf("failed to generate %d child key: %w", i+1, err)
 		}
 	}
+	key.Version = version
 
 	return key, nil
 }
 
-func extendedKeyToKey(key *bip32.Key) (*Key, error) {
-	var network string
-	var params *chaincfg.Params
+// keyParams are resolved parameters required to present an extended key
+type keyParams struct {
+	coinType   string
+	network    string
+	addrType   string
+	pubVersion []byte
+	params     *chaincfg.Params
+	// addressTypes limits generated addresses to these script types,
+	// all single key addresses are generated when empty
+	addressTypes map[string]bool
+}
 
-	if _, ok := mainnetVersions[hex.EncodeToString(key.Version)]; ok {
-		params = &chaincfg.MainNetParams
-		network = NetworkTypeMainnet
-	} else {
-		if _, ok := testnetVersions[hex.EncodeToString(key.Version)]; ok {
-			params = &chaincfg.TestNet3Params
-			network = NetworkTypeTestnet
+// generatesAddress reports whether address of the script type is generated
+func (kp *keyParams) generatesAddress(scriptType string) bool {
+	return len(kp.addressTypes) == 0 || kp.addressTypes[scriptType]
+}
+
+// normalizeAddressTypes normalizes script types of Config.AddressTypes,
+// which must be single key script types with a key address
+func normalizeAddressTypes(addressTypes []string) (map[string]bool, error) {
+	if len(addressTypes) == 0 {
+		return nil, nil
+	}
+
+	m := make(map[string]bool, len(addressTypes))
+	for _, addressType := range addressTypes {
+		scriptType := normalizeAddrType(strings.ToLower(addressType))
+		switch scriptType {
+		case AddrTypeP2pkhOrP2sh, AddrTypeP2wpkhP2sh, AddrTypeP2wpkh:
+			m[scriptType] = true
+		default:
+			return nil, fmt.Errorf("invalid address type %s, allowed address types are %v", addressType,
+				[]string{AddrTypeLegacy, AddrTypeSegWitCompatible, AddrTypeSegWitNative})
 		}
 	}
 
-	if len(network) == 0 {
+	return m, nil
+}
+
+// versionKeyParams resolves key params from an extended key version
+// registered for mainnet or testnet. Key versions unique to a registered
+// coin identify the coin, otherwise the key version is resolved for the
+// coin type, defaulting to btc when empty. Coins such as zec and ltc share
+// key versions with btc, therefore, their keys are resolved as btc keys
+// unless the coin type is given. Callers that already know the network and
+// script type, such as New, should construct key params directly instead.
+func versionKeyParams(version []byte, coinType string) (*keyParams, error) {
+	coinType = strings.ToLower(coinType)
+
+	network, ok := versionNetwork(version)
+	if !ok {
 		return nil, fmt.Errorf("unsupported network and/or coin type, accepted values are BTC:%v",
 			[]string{NetworkTypeMainnet, NetworkTypeTestnet})
 	}
 
+	// key versions unique to a registered coin identify the coin
+	if registeredCoinType, ok := versionCoinType(version); ok {
+		if len(coinType) > 0 && coinType != registeredCoinType {
+			return nil, fmt.Errorf("key version %x belongs to coin type %s, not %s",
+				version, registeredCoinType, coinType)
+		}
+		coinType = registeredCoinType
+	}
+
+	if len(coinType) == 0 {
+		coinType = CoinTypeBtc
+	}
+
+	addrType := versionAddrType(version)
+	pubVersion, ok := lookupKeyVersion(coinType, network, addrType, KeyTypePub)
+	if !ok {
+		return nil, fmt.Errorf("key version %x is not registered for coin type %s on %s",
+			version, coinType, network)
+	}
+
+	if prvVersion, _ := lookupKeyVersion(coinType, network, addrType, KeyTypePrv); !bytes.Equal(version, pubVersion) &&
+		!bytes.Equal(version, prvVersion) {
+		return nil, fmt.Errorf("key version %x is not registered for coin type %s on %s",
+			version, coinType, network)
+	}
+
+	params := networkParams(coinType, network)
+	if params == nil {
+		return nil, fmt.Errorf("%w for coin type %s on %s", ErrMissingNetworkParams, coinType, network)
+	}
+
+	return &keyParams{
+		coinType:   coinType,
+		network:    network,
+		addrType:   addrType,
+		pubVersion: pubVersion,
+		params:     params,
+	}, nil
+}
+
+// extendedKeyToKey converts extended key to Key using
+// resolved key params
+func extendedKeyToKey(key *bip32.Key, kp *keyParams) (*Key, error) {
+	coinType, network, params := kp.coinType, kp.network, kp.params
+
 	var pubKey *bip32.Key
 	var prvKey *bip32.Key
 
@@ -508,9 +1630,12 @@ func extendedKeyToKey(key *bip32.Key) (*Key, error) {
 	var addr string
 	var prvKeyWif string
 
+	isHardened := binary.BigEndian.Uint32(key.ChildNumber) >= bip32.FirstHardenedChild
+
 	if key.IsPrivate {
 		prvKey = key
 		pubKey = key.PublicKey()
+		pubKey.Version = kp.pubVersion
 	} else {
 		pubKey = key
 	}
@@ -539,37 +1664,76 @@ func extendedKeyToKey(key *bip32.Key) (*Key, error) {
 		serializedPubKey = p.SerializeCompressed()
 	}
 
-	addressPubKey, err := btcutil.NewAddressPubKey(serializedPubKey, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate new address from pub key: %w", err)
+	// p2wsh-p2sh and p2wsh key versions (Ypub, Zpub etc.) belong to
+	// multisig script types, whose addresses require keys of all cosigners,
+	// therefore, a single key address would be misleading and is not
+	// generated for such keys. Use Multisig to generate such addresses.
+	switch kp.addrType {
+	case AddrTypeP2wshP2sh, AddrTypeP2wsh:
+		return &Key{
+			XPrv:         prvKeyString,
+			XPub:         pubKeyString,
+			PrvKeyWif:    prvKeyWif,
+			PubKeyHex:    hex.EncodeToString(pubKey.Key),
+			Network:      network,
+			CoinType:     coinType,
+			IsPrivate:    key.IsPrivate,
+			IsCompressed: true,
+			IsHardened:   isHardened,
+			params:       params,
+		}, nil
 	}
 
-	addr = addressPubKey.EncodeAddress()
-
-	// generate a normal p2wkh address from the pubkey hash
-	witnessProg := btcutil.Hash160(serializedPubKey)
-	addressWitnessPubKeyHash, err := btcutil.NewAddressWitnessPubKeyHash(witnessProg, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate new address witness pub key hash: %w", err)
+	var err error
+	if kp.generatesAddress(AddrTypeP2pkhOrP2sh) {
+		// zcash transparent addresses use two byte prefixes
+		if coinType == CoinTypeZec {
+			prefix := zecAddrPrefixes[network]
+			addr = base58.CheckEncode(
+				append(append([]byte{}, prefix[1:]...), btcutil.Hash160(serializedPubKey)...),
+				prefix[0],
+			)
+		} else {
+			addr, err = scriptTypeAddress(serializedPubKey, AddrTypeP2pkhOrP2sh, params)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	segwitBech32 := addressWitnessPubKeyHash.EncodeAddress()
-
-	// generate an address which is
-	// backwards compatible to Bitcoin nodes running 0.6.0 onwards, but
-	// allows us to take advantage of segwit's scripting improvments,
-	// and malleability fixes.
-	serializedScript, err := txscript.PayToAddrScript(addressWitnessPubKeyHash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate pay to addr script: %w", err)
+	// segwit addresses are left empty for coins and networks not
+	// supporting segwit, since such addresses would belong to a
+	// different coin
+	if coinType == CoinTypeZec || !supportsSegWit(coinType, params) {
+		return &Key{
+			XPrv:         prvKeyString,
+			XPub:         pubKeyString,
+			PrvKeyWif:    prvKeyWif,
+			PubKeyHex:    hex.EncodeToString(pubKey.Key),
+			Addr:         addr,
+			Network:      network,
+			CoinType:     coinType,
+			IsPrivate:    key.IsPrivate,
+			IsCompressed: true,
+			IsHardened:   isHardened,
+			params:       params,
+		}, nil
 	}
 
-	addressScriptHash, err := btcutil.NewAddressScriptHash(serializedScript, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate new address script hash: %w", err)
+	var segwitNested, segwitBech32 string
+	if kp.generatesAddress(AddrTypeP2wpkhP2sh) {
+		segwitNested, err = scriptTypeAddress(serializedPubKey, AddrTypeP2wpkhP2sh, params)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	segwitNested := addressScriptHash.EncodeAddress()
+	if kp.generatesAddress(AddrTypeP2wpkh) {
+		segwitBech32, err = scriptTypeAddress(serializedPubKey, AddrTypeP2wpkh, params)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &Key{
 		XPrv:         prvKeyString,
@@ -580,51 +1744,59 @@ func extendedKeyToKey(key *bip32.Key) (*Key, error) {
 		segWitNested: segwitNested,
 		segWitBech32: segwitBech32,
 		Network:      network,
-		CoinType:     CoinTypeBtc,
+		CoinType:     coinType,
+		IsPrivate:    key.IsPrivate,
+		IsCompressed: true,
+		IsHardened:   isHardened,
+		params:       params,
 	}, nil
 }
 
 func Validate(keyString string) error {
+	if err := checkInputLength(keyString); err != nil {
+		return err
+	}
+
 	key, err := bip32.B58Deserialize(keyString)
 	if err != nil {
 		return fmt.Errorf("failed to decode key: %w", err)
 	}
 
-	versionFound := false
-	for k, version := range keyVersions {
-		if bytes.Equal(key.Version, version) {
-			switch path.Base(k) {
-			case KeyTypePub:
-				if key.IsPrivate {
-					return fmt.Errorf("key is marked private, however, key version is public")
-				}
-			case KeyTypePrv:
-				if !key.IsPrivate {
-					return fmt.Errorf("key is marked public, however, key version is private")
-				}
-			}
-			versionFound = true
+	var entry *keyVersionEntry
+	entries := keyVersionEntries()
+	for i := range entries {
+		if bytes.Equal(key.Version, entries[i].version) {
+			entry = &entries[i]
 			break
 		}
 	}
-	if !versionFound {
+	if entry == nil {
 		return fmt.Errorf("unknown key version found")
 	}
 
-	if !key.IsPrivate && key.Key[0] == 4 {
-		return fmt.Errorf("invalid public key prefix 04")
+	switch entry.keyType {
+	case KeyTypePub:
+		if key.IsPrivate {
+			return fmt.Errorf("key is marked private, however, key version is public")
+		}
+	case KeyTypePrv:
+		if !key.IsPrivate {
+			return fmt.Errorf("key is marked public, however, key version is private")
+		}
 	}
 
-	if key.IsPrivate && key.Key[0] == 4 {
-		return fmt.Errorf("invalid private key prefix 04")
+	// guard against truncated keys before accessing key bytes
+	if key.IsPrivate && len(key.Key) != 32 {
+		return fmt.Errorf("invalid private key length %d, expected 32 bytes", len(key.Key))
 	}
 
-	if !key.IsPrivate && key.Key[0] == 1 {
-		return fmt.Errorf("invalid public key prefix 01")
+	if !key.IsPrivate && len(key.Key) != btcec.PubKeyBytesLenCompressed {
+		return fmt.Errorf("invalid public key length %d, expected %d bytes",
+			len(key.Key), btcec.PubKeyBytesLenCompressed)
 	}
 
-	if key.IsPrivate && key.Key[0] == 1 {
-		return fmt.Errorf("invalid private key prefix 01")
+	if err := ValidateKeyPrefix(key.IsPrivate, base58.Decode(keyString)[45]); err != nil {
+		return err
 	}
 
 	if key.Depth == 0 {
@@ -644,35 +1816,107 @@ func Validate(keyString string) error {
 	}
 
 	if key.IsPrivate {
-		n := new(big.Int)
-		var z *big.Int
-		var acc big.Accuracy
+		if err := validatePrivateKeyRange(key.Key); err != nil {
+			return err
+		}
+	}
 
-		if f, _, err := big.ParseFloat(BigZ, 10, 0, big.ToNearestEven); err != nil {
-			return fmt.Errorf("failed to big parse float 0")
-		} else {
-			z, acc = f.Int(z)
-			if acc != big.Exact {
-				return fmt.Errorf("exact accuracy not found in computing z")
+	return nil
+}
+
+// ValidateAll validates extended keys concurrently using workers goroutines,
+// or one goroutine per CPU when workers is not positive, and returns errors
+// aligned by index with input keys, i.e., nil for each valid key
+func ValidateAll(keys []string, workers int) []error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+
+	errs := make([]error, len(keys))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				errs[index] = Validate(keys[index])
 			}
-		}
+		}()
+	}
 
-		bigN, err := base64.StdEncoding.DecodeString(BigN)
-		if err != nil {
-			return fmt.Errorf("failed to base64 decode big N")
-		}
-		n.SetBytes(bigN)
+	for i := range keys {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
 
-		x := new(big.Int)
-		x.SetBytes(key.Key)
+	return errs
+}
 
-		if x.Cmp(n) != -1 {
-			return fmt.Errorf("key is not in 1:n-1, key is too large")
-		}
+// ValidateWIF validates a wif formatted private key by checking that it
+// belongs to a supported network, that the private key is in valid range
+// and that it re-encodes to the same wif
+func ValidateWIF(wifString string) error {
+	if err := checkInputLength(wifString); err != nil {
+		return err
+	}
+
+	wif, err := btcutil.DecodeWIF(wifString)
+	if err != nil {
+		return fmt.Errorf("failed to decode wif: %w", err)
+	}
+
+	if _, _, ok := wifNetwork(wif); !ok {
+		return fmt.Errorf("detected network is not supported, only btc mainnet and testnet keys are supported")
+	}
+
+	if err := validatePrivateKeyRange(wif.PrivKey.Serialize()); err != nil {
+		return err
+	}
 
-		if x.Cmp(z) != 1 {
-			return fmt.Errorf("key is not in 1:n-1, key is too small")
+	if wif.String() != wifString {
+		return fmt.Errorf("wif does not re-encode to the same string")
+	}
+
+	return nil
+}
+
+// ValidateKeyPrefix validates the first byte of the 33 byte key data of a
+// serialized extended key, which must be 00 for private keys, followed by
+// the 32 byte private key, and 02 or 03 for compressed pub keys. Prefixes
+// such as 04 of uncompressed pub keys or 01 are rejected.
+func ValidateKeyPrefix(isPrivate bool, keyByte0 byte) error {
+	if isPrivate {
+		if keyByte0 != 0 {
+			return fmt.Errorf("invalid private key prefix %02x", keyByte0)
 		}
+		return nil
+	}
+
+	if keyByte0 != 2 && keyByte0 != 3 {
+		return fmt.Errorf("invalid public key prefix %02x", keyByte0)
+	}
+
+	return nil
+}
+
+// validatePrivateKeyRange checks private key is in 1:n-1,
+// where n is the order of secp256k1 curve
+func validatePrivateKeyRange(prvKey []byte) error {
+	x := new(big.Int).SetBytes(prvKey)
+
+	if x.Cmp(btcec.S256().N) != -1 {
+		return fmt.Errorf("key is not in 1:n-1, key is too large")
+	}
+
+	if x.Sign() != 1 {
+		return fmt.Errorf("key is not in 1:n-1, key is too small")
 	}
 
 	return nil
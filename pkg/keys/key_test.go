@@ -0,0 +1,1036 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/tyler-smith/go-bip32"
+)
+
+func TestNew_Zec(t *testing.T) {
+	seed := TestSeed()
+
+	key, err := New(
+		&Config{
+			Seed:           seed,
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "auto",
+			AddrType:       AddrTypeLegacy,
+			CoinType:       CoinTypeZec,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.DerivationPath != "m/44h/133h/0h/0/0" {
+		t.Fatal("expected derivation path m/44h/133h/0h/0/0, got", key.DerivationPath)
+	}
+
+	expected := "t1XVXWCvpMgBvUaed4XDqWtgQgJSu1Ghz7F"
+	if key.Addr != expected {
+		t.Fatal("expected", expected, ", got", key.Addr)
+	}
+
+	if _, err := New(
+		&Config{
+			Seed:           seed,
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "auto",
+			AddrType:       AddrTypeSegWitNative,
+			CoinType:       CoinTypeZec,
+		},
+	); err == nil {
+		t.Fatal("expected error for segwit addr type on zec")
+	}
+}
+
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+func TestAddressesFromWIF_Taproot(t *testing.T) {
+	seed := TestSeed()
+
+	key, err := New(
+		&Config{
+			Seed:           seed,
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/86h/0h/0h/0/0",
+			AddrType:       AddrTypeLegacy,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addresses, err := AddressesFromWIF(key.PrvKeyWif, []string{AddrTypeTaproot})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"
+	if addresses[AddrTypeTaproot] != expected {
+		t.Fatal("expected", expected, ", got", addresses[AddrTypeTaproot])
+	}
+}
+
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+func TestKey_TaprootKeys(t *testing.T) {
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/86h/0h/0h/0/0",
+			AddrType:       AddrTypeLegacy,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taprootKeys, err := key.TaprootKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"
+	if taprootKeys.InternalKey != expected {
+		t.Fatal("expected internal key", expected, ", got", taprootKeys.InternalKey)
+	}
+
+	expected = "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"
+	if taprootKeys.OutputKey != expected {
+		t.Fatal("expected output key", expected, ", got", taprootKeys.OutputKey)
+	}
+}
+
+func TestPlanDerivation(t *testing.T) {
+	errs := PlanDerivation(
+		[]*Config{
+			{
+				Seed:           TestSeed(),
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "m/84h/0h/0h/0/0",
+				AddrType:       AddrTypeP2wpkh,
+			},
+			{
+				Seed:           TestSeed(),
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "m/84h/0h/0x/0/0",
+				AddrType:       AddrTypeP2wpkh,
+			},
+			{
+				Seed:           TestSeed(),
+				Network:        "regtest2",
+				DerivationPath: "m/84h/0h/0h/0/0",
+				AddrType:       AddrTypeP2wpkh,
+			},
+		},
+	)
+
+	if len(errs) != 3 {
+		t.Fatal("expected 3 errors, got", len(errs))
+	}
+
+	if errs[0] != nil {
+		t.Fatal(errs[0])
+	}
+
+	if errs[1] == nil || errs[2] == nil {
+		t.Fatal("expected invalid configs to fail planning")
+	}
+}
+
+func TestFormatDerivationPath(t *testing.T) {
+	indices := []uint32{84 + bip32.FirstHardenedChild, bip32.FirstHardenedChild, bip32.FirstHardenedChild, 0, 5}
+
+	if path := FormatDerivationPath(indices, HardenedMarkerApostrophe); path != "m/84'/0'/0'/0/5" {
+		t.Fatal("expected m/84'/0'/0'/0/5, got", path)
+	}
+
+	if path := FormatDerivationPath(indices, HardenedMarkerH); path != "m/84h/0h/0h/0/5" {
+		t.Fatal("expected m/84h/0h/0h/0/5, got", path)
+	}
+}
+
+func TestNew_SeedChecks(t *testing.T) {
+	tests := []struct {
+		name          string
+		seed          []byte
+		allowWeakSeed bool
+		expected      error
+	}{
+		{"all zero", make([]byte, 32), false, ErrWeakSeed},
+		{"all zero allowed", make([]byte, 32), true, nil},
+		{"repeated pattern", bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 8), false, ErrWeakSeed},
+		{"short", []byte{1, 2, 3, 4, 5}, false, ErrInvalidSeedLength},
+		{"short allowed", []byte{1, 2, 3, 4, 5}, true, ErrInvalidSeedLength},
+		{"long", append(TestSeed(), 1), false, ErrInvalidSeedLength},
+		{"empty", nil, false, ErrMissingSeed},
+		{"empty allowed", nil, true, ErrMissingSeed},
+		{"valid", TestSeed(), false, nil},
+	}
+
+	for _, test := range tests {
+		_, err := New(
+			&Config{
+				Seed:           test.seed,
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "m/0",
+				AddrType:       AddrTypeLegacy,
+				AllowWeakSeed:  test.allowWeakSeed,
+			},
+		)
+
+		if test.expected == nil {
+			if err != nil {
+				t.Fatal(test.name, err)
+			}
+			continue
+		}
+
+		if !errors.Is(err, test.expected) {
+			t.Fatal(test.name, "expected", test.expected, ", got", err)
+		}
+	}
+}
+
+func TestNew_AddressTypes(t *testing.T) {
+	config := &Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m/84h/0h/0h/0/0",
+		AddrType:       AddrTypeSegWitNative,
+	}
+
+	key, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Addr != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+		t.Fatal("unexpected default address", key.Addr)
+	}
+
+	// addresses of all single key script types are generated by default
+	bip32Key, err := deserializeExtendedKey(testZpubAbandonAbout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kp, err := versionKeyParams(bip32Key.Version, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := extendedKeyToKey(bip32Key, kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all.Addr) == 0 || len(all.segWitNested) == 0 || len(all.segWitBech32) == 0 {
+		t.Fatal("expected p2pkh, p2sh-p2wpkh and p2wpkh addresses to be generated by default")
+	}
+
+	config.AddressTypes = []string{AddrTypeP2wpkh}
+	limited, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if limited.Addr != key.Addr {
+		t.Fatal("expected", key.Addr, ", got", limited.Addr)
+	}
+
+	config.AddressTypes = []string{AddrTypeLegacy}
+	limited, err = New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(limited.Addr) != 0 {
+		t.Fatal("expected no address when script type is not listed, got", limited.Addr)
+	}
+
+	config.AddressTypes = []string{AddrTypeP2tr}
+	if _, err := New(config); err == nil {
+		t.Fatal("expected error for unsupported address type")
+	}
+}
+
+func TestDecodePublicHexWithNetwork_Uncompressed(t *testing.T) {
+	// pub keys of private key 1
+	tests := []struct {
+		pubKeyHex    string
+		isCompressed bool
+		addr         string
+	}{
+		{
+			pubKeyHex:    "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			isCompressed: true,
+			addr:         "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		},
+		{
+			pubKeyHex: "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+				"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			isCompressed: false,
+			addr:         "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm",
+		},
+	}
+
+	for _, test := range tests {
+		key, err := DecodePublicHexWithNetwork(test.pubKeyHex, NetworkTypeMainnet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key.IsCompressed != test.isCompressed || key.Addr != test.addr {
+			t.Fatal("expected", test.addr, "compressed", test.isCompressed, ", got", key.Addr, key.IsCompressed)
+		}
+	}
+}
+
+func TestReadValidated(t *testing.T) {
+	input := "invalid\nstill invalid\n" + testZpubAbandonAbout + "\n"
+
+	var output bytes.Buffer
+	key, err := ReadValidated(strings.NewReader(input), &output, Validate, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != testZpubAbandonAbout {
+		t.Fatal("expected", testZpubAbandonAbout, ", got", key)
+	}
+
+	if n := strings.Count(output.String(), "invalid input"); n != 2 {
+		t.Fatal("expected 2 invalid input messages, got", n)
+	}
+
+	if _, err := ReadValidated(strings.NewReader(input), io.Discard, Validate, 2); err == nil {
+		t.Fatal("expected error after exhausting attempts")
+	}
+}
+
+func TestVerifyDerivation(t *testing.T) {
+	if err := VerifyDerivation(TestSeed(), "m/84h/0h/0h/0/0", NetworkTypeMainnet, AddrTypeP2wpkh,
+		"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"); err != nil {
+		t.Fatal(err)
+	}
+
+	// address at index 1
+	err := VerifyDerivation(TestSeed(), "m/84h/0h/0h/0/0", NetworkTypeMainnet, AddrTypeP2wpkh,
+		"bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g")
+	if !errors.Is(err, ErrAddressMismatch) {
+		t.Fatal("expected ErrAddressMismatch, got", err)
+	}
+}
+
+func TestNew_SeedHex(t *testing.T) {
+	tests := []struct {
+		seed    []byte
+		seedHex string
+		isValid bool
+	}{
+		{nil, testSeed, true},
+		{nil, "0x" + testSeed, true},
+		{nil, " " + testSeed + "\n", true},
+		{nil, "0x" + testSeed[:len(testSeed)-1] + "g", false},
+		{nil, testSeed[1:], false},
+		{TestSeed(), testSeed, false},
+	}
+
+	for _, test := range tests {
+		key, err := New(
+			&Config{
+				Seed:           test.seed,
+				SeedHex:        test.seedHex,
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "m/84h/0h/0h/0/0",
+				AddrType:       AddrTypeP2wpkh,
+			},
+		)
+
+		if !test.isValid {
+			if err == nil {
+				t.Fatal("expected error for seed hex", test.seedHex)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key.Addr != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+			t.Fatal("unexpected address", key.Addr)
+		}
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	keys := []string{
+		testZpubAbandonAbout,
+		"invalid",
+		testZpubAbandonAbout[:len(testZpubAbandonAbout)-1] + "t",
+		testZpubAbandonAbout,
+	}
+
+	for _, workers := range []int{0, 1, 2, 10} {
+		errs := ValidateAll(keys, workers)
+		if len(errs) != len(keys) {
+			t.Fatal("expected", len(keys), "errors, got", len(errs))
+		}
+
+		if errs[0] != nil || errs[3] != nil {
+			t.Fatal("expected valid keys to have no error, got", errs[0], errs[3])
+		}
+
+		if errs[1] == nil || errs[2] == nil {
+			t.Fatal("expected invalid keys to have errors")
+		}
+	}
+
+	if errs := ValidateAll(nil, 0); len(errs) != 0 {
+		t.Fatal("expected no errors for empty input, got", len(errs))
+	}
+}
+
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+func TestRawDerive(t *testing.T) {
+	prvKey, chainCode, err := RawDerive(mustDecodeHex(TestVector1Seed), "m/0h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"
+	if hex.EncodeToString(prvKey.Serialize()) != expected {
+		t.Fatal("expected private key", expected, ", got", hex.EncodeToString(prvKey.Serialize()))
+	}
+
+	expected = "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"
+	if hex.EncodeToString(chainCode) != expected {
+		t.Fatal("expected chain code", expected, ", got", hex.EncodeToString(chainCode))
+	}
+}
+
+func TestDeriveIndices(t *testing.T) {
+	key, err := DeriveIndices(testZpubAbandonAbout, []uint32{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := Derive(testZpubAbandonAbout, "m/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Addr != expected.Addr || key.DerivationPath != expected.DerivationPath {
+		t.Fatal("expected", expected.DerivationPath, expected.Addr, ", got", key.DerivationPath, key.Addr)
+	}
+
+	if key.Addr != "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g" {
+		t.Fatal("unexpected address", key.Addr)
+	}
+
+	// hardened child cannot be derived from a pub key
+	if _, err := DeriveIndices(testZpubAbandonAbout, []uint32{bip32.FirstHardenedChild}); err == nil {
+		t.Fatal("expected error for hardened derivation of pub key")
+	}
+}
+
+func TestKey_EqualKeyMaterial(t *testing.T) {
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/84h/0h/0h/0/0",
+			AddrType:       AddrTypeP2wpkh,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// same key derived from account zpub carries no private key
+	derived, err := Derive(testZpubAbandonAbout, "m/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !key.EqualKeyMaterial(derived) {
+		t.Fatal("expected keys to have equal key material")
+	}
+
+	other, err := Derive(testZpubAbandonAbout, "m/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.EqualKeyMaterial(other) {
+		t.Fatal("expected keys at different indices to differ")
+	}
+
+	if key.EqualKeyMaterial(nil) {
+		t.Fatal("expected key to differ from nil key")
+	}
+}
+
+func TestNew_IsHardened(t *testing.T) {
+	tests := []struct {
+		derivationPath string
+		isHardened     bool
+	}{
+		{"m/0h", true},
+		{"m/0", false},
+		{"m/0h/1", false},
+		{"m/0/1'", true},
+	}
+
+	for _, test := range tests {
+		key, err := New(
+			&Config{
+				Seed:           TestSeed(),
+				Network:        NetworkTypeMainnet,
+				DerivationPath: test.derivationPath,
+				AddrType:       AddrTypeLegacy,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key.IsHardened != test.isHardened {
+			t.Fatal(test.derivationPath, "expected", test.isHardened, ", got", key.IsHardened)
+		}
+	}
+}
+
+func TestNew_MissingScriptType(t *testing.T) {
+	config := &Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m/84h/0h/0h/0/0",
+	}
+
+	if err := config.Validate(); !errors.Is(err, ErrMissingScriptType) {
+		t.Fatal("expected ErrMissingScriptType, got", err)
+	}
+
+	if _, err := New(config); !errors.Is(err, ErrMissingScriptType) {
+		t.Fatal("expected ErrMissingScriptType, got", err)
+	}
+}
+
+func TestNew_DerivationIndices(t *testing.T) {
+	expected, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/84h/0h/0h/0/1",
+			AddrType:       AddrTypeP2wpkh,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := New(
+		&Config{
+			Seed:              TestSeed(),
+			Network:           NetworkTypeMainnet,
+			DerivationIndices: []uint32{84 + bip32.FirstHardenedChild, bip32.FirstHardenedChild, bip32.FirstHardenedChild, 0, 1},
+			AddrType:          AddrTypeP2wpkh,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Addr != expected.Addr || key.XPrv != expected.XPrv || key.DerivationPath != expected.DerivationPath {
+		t.Fatal("expected", expected.DerivationPath, expected.Addr, ", got", key.DerivationPath, key.Addr)
+	}
+
+	if key.Addr != "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g" {
+		t.Fatal("unexpected address", key.Addr)
+	}
+
+	if _, err := New(
+		&Config{
+			Seed:              TestSeed(),
+			Network:           NetworkTypeMainnet,
+			DerivationPath:    "m/84h/0h/0h/0/1",
+			DerivationIndices: []uint32{0},
+			AddrType:          AddrTypeP2wpkh,
+		},
+	); err == nil {
+		t.Fatal("expected error when both derivation path and indices are set")
+	}
+}
+
+func TestNew_ResolvedIndices(t *testing.T) {
+	for _, derivationPath := range []string{"m/84'/0'/0'/0/1", "m/84H/0H/0H/0/1", "m/84h/0h/0h/0/1"} {
+		key, err := New(
+			&Config{
+				Seed:           TestSeed(),
+				Network:        NetworkTypeMainnet,
+				DerivationPath: derivationPath,
+				AddrType:       AddrTypeP2wpkh,
+			},
+		)
+		if err != nil {
+			t.Fatal(derivationPath, err)
+		}
+
+		if key.DerivationPath != "m/84h/0h/0h/0/1" {
+			t.Fatal(derivationPath, "expected canonical path m/84h/0h/0h/0/1, got", key.DerivationPath)
+		}
+
+		expected := []uint32{84 + bip32.FirstHardenedChild, bip32.FirstHardenedChild, bip32.FirstHardenedChild, 0, 1}
+		if len(key.ResolvedIndices) != len(expected) {
+			t.Fatal(derivationPath, "expected", expected, ", got", key.ResolvedIndices)
+		}
+
+		for i := range expected {
+			if key.ResolvedIndices[i] != expected[i] {
+				t.Fatal(derivationPath, "expected", expected, ", got", key.ResolvedIndices)
+			}
+		}
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	input := "# account keys\n\n" + testZpubAbandonAbout + "\n  \n\t1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH  \r\n# trailing comment\nlast"
+
+	lines, err := ReadAll(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{testZpubAbandonAbout, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", "last"}
+	if len(lines) != len(expected) {
+		t.Fatal("expected", expected, ", got", lines)
+	}
+
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Fatal("expected", expected[i], ", got", lines[i])
+		}
+	}
+
+	_, err = ReadAll(strings.NewReader("a\nb\n" + strings.Repeat("c", MaxInputLength+1) + "\n"))
+	if !errors.Is(err, ErrInputTooLong) || !strings.Contains(err.Error(), "line 3") {
+		t.Fatal("expected ErrInputTooLong at line 3, got", err)
+	}
+}
+
+func TestNew_ConfigParamsOverride(t *testing.T) {
+	config := &Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m/84h/0h/0h/0/0",
+		AddrType:       AddrTypeP2wpkh,
+		Bech32HRP:      "vtc",
+	}
+
+	key, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// same witness program as bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu
+	btcAddr, err := btcutil.DecodeAddress("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := chaincfg.MainNetParams
+	params.Bech32HRPSegwit = "vtc"
+	expected, err := btcutil.NewAddressWitnessPubKeyHash(btcAddr.ScriptAddress(), &params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(key.Addr, "vtc1q") || key.Addr != expected.EncodeAddress() {
+		t.Fatal("expected", expected.EncodeAddress(), ", got", key.Addr)
+	}
+
+	config.Bech32HRP = "VTC"
+	if _, err := New(config); err == nil {
+		t.Fatal("expected error for uppercase bech32 hrp")
+	}
+}
+
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki#test-vectors
+func TestNew_Taproot(t *testing.T) {
+	for _, addrType := range []string{AddrTypeP2tr, AddrTypeTaproot, AddrTypeBip86} {
+		key, err := New(
+			&Config{
+				Seed:           TestSeed(),
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "auto",
+				AddrType:       addrType,
+			},
+		)
+		if err != nil {
+			t.Fatal(addrType, err)
+		}
+
+		if key.DerivationPath != "m/86h/0h/0h/0/0" {
+			t.Fatal("expected derivation path m/86h/0h/0h/0/0, got", key.DerivationPath)
+		}
+
+		expected := "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"
+		if key.Addr != expected || key.PrimaryAddress != expected {
+			t.Fatal("expected", expected, ", got", key.Addr)
+		}
+
+		if !strings.HasPrefix(key.XPub, "xpub") {
+			t.Fatal("expected xpub key version, got", key.XPub)
+		}
+	}
+
+	if _, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "auto",
+			AddrType:       AddrTypeTaproot,
+			CoinType:       CoinTypeLtc,
+		},
+	); err == nil {
+		t.Fatal("expected error for taproot addr type on ltc")
+	}
+}
+
+func TestRead_MaxInputLength(t *testing.T) {
+	long := strings.Repeat("a", MaxInputLength+1)
+
+	if _, err := Read(strings.NewReader(long + "\n")); !errors.Is(err, ErrInputTooLong) {
+		t.Fatal("expected ErrInputTooLong, got", err)
+	}
+
+	input, err := Read(strings.NewReader(long+"\n"), WithMaxInputLength(2*MaxInputLength))
+	if err != nil || input != long {
+		t.Fatal("expected long input to be read with larger max input length, got", err)
+	}
+
+	if _, err := Read(strings.NewReader(testZpubAbandonAbout+"\n"), WithMaxInputLength(10)); !errors.Is(err, ErrInputTooLong) {
+		t.Fatal("expected ErrInputTooLong, got", err)
+	}
+
+	lines, err := ReadAll(strings.NewReader("a\n"+long+"\n"), WithMaxInputLength(2*MaxInputLength))
+	if err != nil || len(lines) != 2 {
+		t.Fatal("expected 2 lines with larger max input length, got", len(lines), err)
+	}
+
+	if _, err := ReadAll(strings.NewReader("abc\n"), WithMaxInputLength(2)); !errors.Is(err, ErrInputTooLong) {
+		t.Fatal("expected ErrInputTooLong, got", err)
+	}
+
+	if _, err := ReadAll(strings.NewReader("abc\n"), WithMaxInputLength(0)); err == nil {
+		t.Fatal("expected error for non-positive max input length")
+	}
+}
+
+func TestConfig_Validate_EnforceBIP44Hardening(t *testing.T) {
+	tests := []struct {
+		derivationPath string
+		wantErr        bool
+	}{
+		{derivationPath: "m/44/0h/0h", wantErr: true},
+		{derivationPath: "m/44h/0/0h/0/0", wantErr: true},
+		{derivationPath: "m/44h/0h/0/0/0", wantErr: true},
+		{derivationPath: "m/44h/0h/0h", wantErr: false},
+		{derivationPath: "m/44h/0h/0h/0/0", wantErr: false},
+		{derivationPath: "m/0/0", wantErr: false},
+	}
+
+	for _, test := range tests {
+		config := &Config{
+			Seed:                  TestSeed(),
+			Network:               NetworkTypeMainnet,
+			DerivationPath:        test.derivationPath,
+			AddrType:              AddrTypeLegacy,
+			EnforceBIP44Hardening: true,
+		}
+
+		if err := config.Validate(); (err != nil) != test.wantErr {
+			t.Fatal(test.derivationPath, "expected error:", test.wantErr, ", got", err)
+		}
+
+		// unhardened levels are accepted unless enforced
+		config.EnforceBIP44Hardening = false
+		if err := config.Validate(); err != nil {
+			t.Fatal(test.derivationPath, err)
+		}
+	}
+}
+
+func TestConfig_Validate_EnforceCoinTypeIndex(t *testing.T) {
+	tests := []struct {
+		network        string
+		coinType       string
+		derivationPath string
+		wantErr        bool
+	}{
+		{network: NetworkTypeMainnet, coinType: CoinTypeBtc, derivationPath: "m/44h/1h/0h/0/0", wantErr: true},
+		{network: NetworkTypeMainnet, coinType: CoinTypeBtc, derivationPath: "m/44h/0h/0h/0/0", wantErr: false},
+		{network: NetworkTypeTestnet, coinType: CoinTypeBtc, derivationPath: "m/44h/0h/0h/0/0", wantErr: true},
+		{network: NetworkTypeTestnet, coinType: CoinTypeBtc, derivationPath: "m/44h/1h/0h/0/0", wantErr: false},
+		{network: NetworkTypeMainnet, coinType: CoinTypeLtc, derivationPath: "m/44h/0h/0h/0/0", wantErr: true},
+		{network: NetworkTypeMainnet, coinType: CoinTypeLtc, derivationPath: "m/44h/2h/0h/0/0", wantErr: false},
+		{network: NetworkTypeMainnet, coinType: CoinTypeZec, derivationPath: "m/44h/133h/0h/0/0", wantErr: false},
+	}
+
+	for _, test := range tests {
+		config := &Config{
+			Seed:                 TestSeed(),
+			Network:              test.network,
+			DerivationPath:       test.derivationPath,
+			AddrType:             AddrTypeLegacy,
+			CoinType:             test.coinType,
+			EnforceCoinTypeIndex: true,
+		}
+
+		if err := config.Validate(); (err != nil) != test.wantErr {
+			t.Fatal(test.coinType, test.network, test.derivationPath, "expected error:", test.wantErr, ", got", err)
+		}
+
+		if _, err := New(config); (err != nil) != test.wantErr {
+			t.Fatal(test.coinType, test.network, test.derivationPath, "expected error:", test.wantErr, ", got", err)
+		}
+	}
+}
+
+func TestDeriveIndices_DepthOverflow(t *testing.T) {
+	master, err := New(&Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m",
+		AddrType:       AddrTypeLegacy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DeriveIndices(master.XPrv, make([]uint32, 256)); !errors.Is(err, ErrDepthOverflow) {
+		t.Fatal("expected ErrDepthOverflow, got", err)
+	}
+
+	deepest, err := DeriveIndices(master.XPrv, make([]uint32, 255))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Derive(deepest.XPrv, "m/0"); !errors.Is(err, ErrDepthOverflow) {
+		t.Fatal("expected ErrDepthOverflow, got", err)
+	}
+
+	if _, err := Derive(deepest.XPub, "m/0"); !errors.Is(err, ErrDepthOverflow) {
+		t.Fatal("expected ErrDepthOverflow, got", err)
+	}
+}
+
+func TestRead_BOMAndCRLF(t *testing.T) {
+	master, err := New(&Config{
+		Seed:           TestSeed(),
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "m",
+		AddrType:       AddrTypeLegacy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := Read(strings.NewReader("\ufeff" + master.XPrv + "\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if input != master.XPrv {
+		t.Fatalf("expected BOM and CR to be stripped, got %q", input)
+	}
+
+	if _, err := Derive(input, "m/0"); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := ReadAll(strings.NewReader("\ufeff" + master.XPrv + "\r\n" + master.XPub + "\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 2 || lines[0] != master.XPrv || lines[1] != master.XPub {
+		t.Fatalf("expected BOM and CR to be stripped, got %q", lines)
+	}
+}
+
+func TestValidateWIF(t *testing.T) {
+	// private key 1
+	const wifCompressed = "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"
+
+	for _, wif := range []string{
+		wifCompressed,
+		"5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf",
+		"cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA",
+	} {
+		if err := ValidateWIF(wif); err != nil {
+			t.Fatal(wif, err)
+		}
+	}
+
+	badChecksum := wifCompressed[:len(wifCompressed)-1] + "o"
+	if err := ValidateWIF(badChecksum); err == nil || !strings.Contains(err.Error(), "failed to decode wif") {
+		t.Fatal("expected checksum error, got", err)
+	}
+
+	wif, err := btcutil.DecodeWIF(wifCompressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ltc, ok := LookupCoin(CoinTypeLtc)
+	if !ok {
+		t.Fatal("ltc is not registered")
+	}
+
+	ltcWIF, err := btcutil.NewWIF(wif.PrivKey, ltc.Params[NetworkTypeMainnet], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateWIF(ltcWIF.String()); err == nil || !strings.Contains(err.Error(), "network is not supported") {
+		t.Fatal("expected network error for ltc wif, got", err)
+	}
+}
+
+func TestDecodePublicHexMulti(t *testing.T) {
+	// pub keys of private key 1
+	tests := []struct {
+		pubKeyHex    string
+		isCompressed bool
+		mainnetAddr  string
+	}{
+		{
+			pubKeyHex:    "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			isCompressed: true,
+			mainnetAddr:  "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		},
+		{
+			pubKeyHex: "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+				"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			isCompressed: false,
+			mainnetAddr:  "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm",
+		},
+	}
+
+	for _, test := range tests {
+		keys, err := DecodePublicHexMulti(test.pubKeyHex, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(keys) != 2 || keys[NetworkTypeMainnet] == nil || keys[NetworkTypeTestnet] == nil {
+			t.Fatal("expected mainnet and testnet keys, got", keys)
+		}
+
+		for network, key := range keys {
+			if key.Network != network || key.IsCompressed != test.isCompressed {
+				t.Fatal("unexpected key for", network, key.Network, key.IsCompressed)
+			}
+
+			single, err := DecodePublicHexWithNetwork(test.pubKeyHex, network)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key.Addr != single.Addr {
+				t.Fatal("expected", single.Addr, ", got", key.Addr)
+			}
+		}
+
+		if keys[NetworkTypeMainnet].Addr != test.mainnetAddr {
+			t.Fatal("expected", test.mainnetAddr, ", got", keys[NetworkTypeMainnet].Addr)
+		}
+
+		if keys[NetworkTypeTestnet].Addr == keys[NetworkTypeMainnet].Addr {
+			t.Fatal("expected testnet address to differ from mainnet address")
+		}
+
+		// network aliases are normalized
+		keys, err = DecodePublicHexMulti(test.pubKeyHex, []string{"main"})
+		if err != nil || keys[NetworkTypeMainnet] == nil {
+			t.Fatal("expected mainnet key for network alias, got", keys, err)
+		}
+
+		if _, err := DecodePublicHexMulti(test.pubKeyHex, []string{NetworkTypeMainnet, "invalid"}); err == nil {
+			t.Fatal("expected error for invalid network")
+		}
+	}
+
+	if _, err := DecodePublicHexMulti("02"+strings.Repeat("00", 32), nil); err == nil {
+		t.Fatal("expected error for invalid pub key")
+	}
+}
+
+func TestValidateKeyPrefix(t *testing.T) {
+	tests := []struct {
+		isPrivate bool
+		keyByte0  byte
+		wantErr   bool
+	}{
+		{isPrivate: true, keyByte0: 0x00, wantErr: false},
+		{isPrivate: true, keyByte0: 0x01, wantErr: true},
+		{isPrivate: true, keyByte0: 0x02, wantErr: true},
+		{isPrivate: false, keyByte0: 0x02, wantErr: false},
+		{isPrivate: false, keyByte0: 0x03, wantErr: false},
+		{isPrivate: false, keyByte0: 0x04, wantErr: true},
+		{isPrivate: false, keyByte0: 0x00, wantErr: true},
+	}
+
+	for _, test := range tests {
+		if err := ValidateKeyPrefix(test.isPrivate, test.keyByte0); (err != nil) != test.wantErr {
+			t.Fatalf("private: %v, prefix: %02x, expected error: %v, got %v", test.isPrivate, test.keyByte0, test.wantErr, err)
+		}
+	}
+
+	master, err := bip32.NewMasterKey(TestSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keys serialized with each of the SLIP-132 key versions carry a valid prefix
+	for _, entry := range keyVersionEntries() {
+		if entry.coinType != CoinTypeBtc {
+			continue
+		}
+
+		key := *master
+		if entry.keyType == KeyTypePub {
+			key = *master.PublicKey()
+		}
+		key.Version = entry.version
+		keyString := key.B58Serialize()
+
+		if err := ValidateKeyPrefix(key.IsPrivate, base58.Decode(keyString)[45]); err != nil {
+			t.Fatal(entry.network, entry.addrType, entry.keyType, err)
+		}
+
+		if err := Validate(keyString); err != nil {
+			t.Fatal(entry.network, entry.addrType, entry.keyType, err)
+		}
+	}
+
+	// unknown key versions are rejected
+	unknown := master.PublicKey()
+	unknown.Version = mustDecodeHex("deadbeef")
+	if err := Validate(unknown.B58Serialize()); err == nil {
+		t.Fatal("expected error for unknown key version")
+	}
+}
@@ -0,0 +1,73 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/tyler-smith/go-bip32"
+)
+
+// lnd derivation scheme constants. lnd derives keys at
+// m/1017h/coinTypeh/keyFamilyh/0/index, where the node identity key
+// belongs to key family 6. Only key derivation is in scope, no part
+// of the Lightning protocol is implemented.
+// https://github.com/lightningnetwork/lnd/blob/master/keychain/derivation.go
+const (
+	LightningPurpose          = 1017
+	LightningKeyFamilyNodeKey = 6
+)
+
+// LightningKeyIndices returns lnd style derivation indices
+// m/1017h/coinTypeh/keyFamilyh/0/index for a network
+func LightningKeyIndices(network string, keyFamily, index uint32) ([]uint32, error) {
+	var coinType uint32
+	switch normalizeNetwork(network) {
+	case NetworkTypeMainnet:
+		coinType = 0
+	case NetworkTypeTestnet:
+		coinType = 1
+	default:
+		return nil, fmt.Errorf("invalid or unsupported network: %s. allowed networks are %v", network,
+			[]string{NetworkTypeMainnet, NetworkTypeTestnet},
+		)
+	}
+
+	if keyFamily >= bip32.FirstHardenedChild {
+		return nil, fmt.Errorf("key family %d must be less than %d", keyFamily, bip32.FirstHardenedChild)
+	}
+
+	return []uint32{
+		LightningPurpose + bip32.FirstHardenedChild,
+		coinType + bip32.FirstHardenedChild,
+		keyFamily + bip32.FirstHardenedChild,
+		0,
+		index,
+	}, nil
+}
+
+// LightningNodeKey derives the lnd style node identity private key of a seed
+// at m/1017h/coinTypeh/6h/0/0 and returns the raw 32 byte private key
+func LightningNodeKey(seed []byte, network string) ([]byte, error) {
+	indices, err := LightningKeyIndices(network, LightningKeyFamilyNodeKey, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := New(
+		&Config{
+			Seed:              seed,
+			Network:           network,
+			DerivationIndices: indices,
+			AddrType:          AddrTypeP2pkhOrP2sh,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive node key: %w", err)
+	}
+
+	prvKey, err := PrivKey(key.XPrv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get private key: %w", err)
+	}
+
+	return prvKey.Serialize(), nil
+}
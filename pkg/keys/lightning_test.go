@@ -0,0 +1,42 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/tyler-smith/go-bip32"
+)
+
+func TestLightningNodeKey(t *testing.T) {
+	prvKey, err := LightningNodeKey(TestSeed(), NetworkTypeMainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// node pub key at m/1017h/0h/6h/0/0
+	_, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), prvKey)
+	expected := "03e2ed64c913bd000c21be4a48214d89edc26f550deefc795c57b6ed7c4f9a7728"
+	if hex.EncodeToString(pubKey.SerializeCompressed()) != expected {
+		t.Fatal("expected", expected, ", got", hex.EncodeToString(pubKey.SerializeCompressed()))
+	}
+
+	if _, err := LightningNodeKey(TestSeed(), NetworkTypeRegtest); err == nil {
+		t.Fatal("expected error for unsupported network")
+	}
+}
+
+func TestLightningKeyIndices(t *testing.T) {
+	indices, err := LightningKeyIndices(NetworkTypeTestnet, LightningKeyFamilyNodeKey, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path := formatDerivationPath(indices); path != "m/1017h/1h/6h/0/3" {
+		t.Fatal("expected m/1017h/1h/6h/0/3, got", path)
+	}
+
+	if _, err := LightningKeyIndices(NetworkTypeMainnet, bip32.FirstHardenedChild+LightningKeyFamilyNodeKey, 0); err == nil {
+		t.Fatal("expected error for hardened key family")
+	}
+}
@@ -0,0 +1,57 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/tyler-smith/go-bip32"
+)
+
+// BelongsTo reports whether the child extended key is a direct child of
+// the master extended key, i.e., the parent fingerprint of the child matches
+// the fingerprint of the master key and, where the child can be derived from
+// the master key, the derived key matches the child. Relationships spanning
+// more than one level cannot be determined from serialized keys and are
+// reported as an error.
+func BelongsTo(childKeyString, masterKeyString string) (bool, error) {
+	childKey, err := deserializeExtendedKey(childKeyString)
+	if err != nil {
+		return false, fmt.Errorf("failed to deserialize child key: %w", err)
+	}
+
+	masterKey, err := deserializeExtendedKey(masterKeyString)
+	if err != nil {
+		return false, fmt.Errorf("failed to deserialize master key: %w", err)
+	}
+
+	if childKey.Depth <= masterKey.Depth {
+		return false, nil
+	}
+
+	if childKey.Depth > masterKey.Depth+1 {
+		return false, fmt.Errorf("child key is %d levels below master key, only direct children can be verified",
+			childKey.Depth-masterKey.Depth)
+	}
+
+	masterPubKey := masterKey.PublicKey()
+	if !bytes.Equal(childKey.FingerPrint, btcutil.Hash160(masterPubKey.Key)[:4]) {
+		return false, nil
+	}
+
+	childNumber := binary.BigEndian.Uint32(childKey.ChildNumber)
+	if !masterKey.IsPrivate && childNumber >= bip32.FirstHardenedChild {
+		// hardened child cannot be derived from a public key,
+		// fingerprint match is the best available evidence
+		return true, nil
+	}
+
+	derivedKey, err := masterKey.NewChildKey(childNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to derive child key: %w", err)
+	}
+
+	return bytes.Equal(derivedKey.PublicKey().Key, childKey.PublicKey().Key) &&
+		bytes.Equal(derivedKey.ChainCode, childKey.ChainCode), nil
+}
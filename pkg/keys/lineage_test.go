@@ -0,0 +1,69 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/tyler-smith/go-bip32"
+)
+
+func TestBelongsTo(t *testing.T) {
+	master, err := bip32.NewMasterKey(TestSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child, err := master.NewChildKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grandChild, err := child.NewChildKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hardenedChild, err := master.NewChildKey(bip32.FirstHardenedChild)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherMaster, err := bip32.NewMasterKey(mustDecodeHex(TestVector1Seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherChild, err := otherMaster.NewChildKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		child    *bip32.Key
+		master   *bip32.Key
+		expected bool
+	}{
+		{"direct child of xprv", child, master, true},
+		{"direct child of xpub", child.PublicKey(), master.PublicKey(), true},
+		{"fingerprint mismatch", otherChild, master, false},
+		{"hardened child of xprv", hardenedChild, master, true},
+		{"hardened child of xpub", hardenedChild.PublicKey(), master.PublicKey(), true},
+		{"master below child", master, child, false},
+	}
+
+	for _, test := range tests {
+		ok, err := BelongsTo(test.child.B58Serialize(), test.master.B58Serialize())
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+
+		if ok != test.expected {
+			t.Fatal(test.name, "expected", test.expected, ", got", ok)
+		}
+	}
+
+	// relationships spanning more than one level cannot be verified
+	if _, err := BelongsTo(grandChild.B58Serialize(), master.B58Serialize()); err == nil {
+		t.Fatal("expected error for key more than one level below master key")
+	}
+}
@@ -0,0 +1,170 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubetrail/bip39/pkg/mnemonics"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// wordlistsByLanguage are BIP-39 wordlists keyed by lower case language name
+var wordlistsByLanguage = map[string][]string{
+	strings.ToLower(mnemonics.LanguageEnglish):            wordlists.English,
+	strings.ToLower(mnemonics.LanguageJapanese):           wordlists.Japanese,
+	strings.ToLower(mnemonics.LanguageChineseSimplified):  wordlists.ChineseSimplified,
+	strings.ToLower(mnemonics.LanguageChineseTraditional): wordlists.ChineseTraditional,
+	strings.ToLower(mnemonics.LanguageCzech):              wordlists.Czech,
+	strings.ToLower(mnemonics.LanguageFrench):             wordlists.French,
+	strings.ToLower(mnemonics.LanguageItalian):            wordlists.Italian,
+	strings.ToLower(mnemonics.LanguageKorean):             wordlists.Korean,
+	strings.ToLower(mnemonics.LanguageSpanish):            wordlists.Spanish,
+}
+
+// wordIndicesByLanguage maps words of each wordlist to their indices
+var wordIndicesByLanguage = func() map[string]map[string]int {
+	m := make(map[string]map[string]int, len(wordlistsByLanguage))
+	for language, wordlist := range wordlistsByLanguage {
+		m[language] = newWordIndices(wordlist)
+	}
+	return m
+}()
+
+// englishWordIndices maps words of BIP-39 english wordlist to their indices
+var englishWordIndices = wordIndicesByLanguage[strings.ToLower(mnemonics.LanguageEnglish)]
+
+// newWordIndices maps NFKD normalized words of a wordlist to their indices
+func newWordIndices(wordlist []string) map[string]int {
+	wordIndices := make(map[string]int, len(wordlist))
+	for i, word := range wordlist {
+		wordIndices[norm.NFKD.String(word)] = i
+	}
+
+	return wordIndices
+}
+
+// languageWordIndices returns word indices of the wordlist of a language
+func languageWordIndices(language string) (map[string]int, error) {
+	language = strings.ToLower(language)
+	if wordIndices, ok := wordIndicesByLanguage[language]; ok {
+		return wordIndices, nil
+	}
+
+	languages := make([]string, 0, len(wordlistsByLanguage))
+	for k := range wordlistsByLanguage {
+		languages = append(languages, k)
+	}
+	sort.Strings(languages)
+
+	return nil, fmt.Errorf("invalid mnemonic language %s, must be one of %s",
+		language, strings.Join(languages, ", "))
+}
+
+// ValidateMnemonic validates word count, wordlist membership and checksum
+// of a BIP-39 english mnemonic without deriving a seed
+func ValidateMnemonic(mnemonic string) error {
+	return validateMnemonic(mnemonic, englishWordIndices)
+}
+
+// ValidateMnemonicLanguage validates a BIP-39 mnemonic against the wordlist
+// of a language, such as English, Japanese or Spanish
+func ValidateMnemonicLanguage(mnemonic, language string) error {
+	wordIndices, err := languageWordIndices(language)
+	if err != nil {
+		return err
+	}
+
+	return validateMnemonic(mnemonic, wordIndices)
+}
+
+// MnemonicToSeed validates a BIP-39 mnemonic against the wordlist of a language
+// and derives the seed. Mnemonic and passphrase are NFKD normalized per spec,
+// so words may be separated by ideographic spaces as is customary for Japanese.
+func MnemonicToSeed(mnemonic, passphrase, language string) ([]byte, error) {
+	if err := ValidateMnemonicLanguage(mnemonic, language); err != nil {
+		return nil, fmt.Errorf("failed to validate mnemonic: %w", err)
+	}
+
+	mnemonic = strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+
+	return pbkdf2.Key([]byte(mnemonic), []byte(salt), 2048, 64, sha512.New), nil
+}
+
+// validateMnemonic validates mnemonic against word indices of a wordlist
+// https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki#generating-the-mnemonic
+func validateMnemonic(mnemonic string, wordIndices map[string]int) error {
+	if err := checkInputLength(mnemonic); err != nil {
+		return err
+	}
+
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return fmt.Errorf("%w %d, must be 12, 15, 18, 21 or 24", ErrInvalidMnemonicWordCount, len(words))
+	}
+
+	// each word encodes 11 bits, a checksum bit per 32 bits of entropy
+	// is appended to the entropy
+	bits := make([]byte, 0, len(words)*11)
+	for i, word := range words {
+		index, ok := wordIndices[word]
+		if !ok {
+			return fmt.Errorf("%w %q at position %d", ErrUnknownMnemonicWord, word, i+1)
+		}
+
+		for j := 10; j >= 0; j-- {
+			bits = append(bits, byte(index>>j)&1)
+		}
+	}
+
+	checksumLength := len(bits) / 33
+	entropyLength := len(bits) - checksumLength
+
+	entropy := make([]byte, entropyLength/8)
+	for i := 0; i < entropyLength; i++ {
+		entropy[i/8] |= bits[i] << (7 - i%8)
+	}
+
+	hash := sha256.Sum256(entropy)
+	for i := 0; i < checksumLength; i++ {
+		if bits[entropyLength+i] != (hash[i/8]>>(7-i%8))&1 {
+			return ErrInvalidMnemonicChecksum
+		}
+	}
+
+	return nil
+}
+
+// entropyToMnemonic encodes entropy as a mnemonic of words of a wordlist,
+// appending a checksum bit per 32 bits of entropy
+// https://github.com/bitcoin/bips/blob/master/bip-0039.mediawiki#generating-the-mnemonic
+func entropyToMnemonic(entropy []byte, wordlist []string) string {
+	hash := sha256.Sum256(entropy)
+	checksumLength := len(entropy) * 8 / 32
+
+	bits := make([]byte, 0, len(entropy)*8+checksumLength)
+	for i := 0; i < len(entropy)*8; i++ {
+		bits = append(bits, (entropy[i/8]>>(7-i%8))&1)
+	}
+	for i := 0; i < checksumLength; i++ {
+		bits = append(bits, (hash[i/8]>>(7-i%8))&1)
+	}
+
+	words := make([]string, 0, len(bits)/11)
+	for i := 0; i < len(bits); i += 11 {
+		index := 0
+		for _, bit := range bits[i : i+11] {
+			index = index<<1 | int(bit)
+		}
+		words = append(words, wordlist[index])
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,63 @@
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// https://github.com/bip32JP/bip32JP.github.io/blob/master/test_JP_BIP39.json
+func TestMnemonicToSeed_Japanese(t *testing.T) {
+	mnemonic := "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　" +
+		"あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら"
+	passphrase := "㍍ガバヴァぱばぐゞちぢ十人十色"
+	expected := "a262d6fb6122ecf45be09c50492b31f92e9beb7d9a845987a02cefda57a15f9c" +
+		"467a17872029a9e92299b5cbdf306e3a0ee620245cbd508959b6cb7ca637bd55"
+
+	seed, err := MnemonicToSeed(mnemonic, passphrase, "Japanese")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(seed) != expected {
+		t.Fatal("expected", expected, ", got", hex.EncodeToString(seed))
+	}
+
+	if err := ValidateMnemonic(mnemonic); err == nil {
+		t.Fatal("expected error validating japanese mnemonic against english wordlist")
+	}
+}
+
+func TestMnemonicToSeed_English(t *testing.T) {
+	seed, err := MnemonicToSeed(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"", "English")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(seed) != testSeed {
+		t.Fatal("expected", testSeed, ", got", hex.EncodeToString(seed))
+	}
+}
+
+func TestValidateMnemonicLanguage(t *testing.T) {
+	const mnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	for _, language := range []string{"English", "english"} {
+		if err := ValidateMnemonicLanguage(mnemonic, language); err != nil {
+			t.Fatal(language, err)
+		}
+	}
+
+	// abandon is also a french word, however, about is not
+	for _, language := range []string{"Spanish", "French", "Japanese"} {
+		if err := ValidateMnemonicLanguage(mnemonic, language); !errors.Is(err, ErrUnknownMnemonicWord) {
+			t.Fatal(language, "expected ErrUnknownMnemonicWord, got", err)
+		}
+	}
+
+	if err := ValidateMnemonicLanguage(mnemonic, "Klingon"); err == nil {
+		t.Fatal("expected error for unknown language")
+	}
+}
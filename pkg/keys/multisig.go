@@ -0,0 +1,145 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// maxMultisigKeys is the max number of pub keys allowed
+// by OP_CHECKMULTISIG
+const maxMultisigKeys = 20
+
+// Multisig derives each of the input extended keys at the derivation path,
+// which is relative to each of the extended keys, and generates a
+// threshold-of-n multisig p2wsh address. Pub keys are sorted per BIP-67
+// when sortKeys is true. The returned string is the corresponding output
+// descriptor, i.e., wsh(sortedmulti(...)) or wsh(multi(...)), along with
+// its BIP-380 checksum.
+func Multisig(threshold int, xpubs []string, derivationPath string, sortKeys bool) (*Key, string, error) {
+	if len(xpubs) == 0 {
+		return nil, "", fmt.Errorf("at least one extended key is required")
+	}
+
+	if len(xpubs) > maxMultisigKeys {
+		return nil, "", fmt.Errorf("too many extended keys, max allowed is %d", maxMultisigKeys)
+	}
+
+	if threshold < 1 || threshold > len(xpubs) {
+		return nil, "", fmt.Errorf("invalid threshold %d, must be in 1:%d", threshold, len(xpubs))
+	}
+
+	indices, err := parseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+
+	var network string
+	var params *chaincfg.Params
+	descriptorKeys := make([]string, len(xpubs))
+	pubKeys := make([][]byte, len(xpubs))
+
+	for i, keyString := range xpubs {
+		bip32Key, err := deserializeExtendedKey(keyString)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to deserialize key %d: %w", i, err)
+		}
+
+		var descriptorVersion string
+		keyNetwork, _ := versionNetwork(bip32Key.Version)
+		switch keyNetwork {
+		case NetworkTypeMainnet:
+			descriptorVersion = xpub
+			params = &chaincfg.MainNetParams
+		case NetworkTypeTestnet:
+			descriptorVersion = tpub
+			params = &chaincfg.TestNet3Params
+		default:
+			return nil, "", fmt.Errorf("unsupported key version for key %d", i)
+		}
+
+		if len(network) > 0 && network != keyNetwork {
+			return nil, "", fmt.Errorf("key %d belongs to %s, however, previous keys belong to %s",
+				i, keyNetwork, network)
+		}
+		network = keyNetwork
+
+		// descriptors only accept xpub and tpub key versions
+		pubKey := bip32Key.PublicKey()
+		pubKey.Version = mustDecodeHex(descriptorVersion)
+		descriptorKeys[i] = pubKey.B58Serialize()
+
+		childKey, err := extendedKeyToIndexDerivedExtendedKey(pubKey, indices)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to derive key %d: %w", i, err)
+		}
+
+		pubKeys[i] = childKey.Key
+	}
+
+	if sortKeys {
+		// https://github.com/bitcoin/bips/blob/master/bip-0067.mediawiki
+		sort.Slice(pubKeys, func(i, j int) bool {
+			return bytes.Compare(pubKeys[i], pubKeys[j]) < 0
+		})
+	}
+
+	addressPubKeys := make([]*btcutil.AddressPubKey, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		p, err := btcec.ParsePubKey(pubKey, btcec.S256())
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse pubkey: %w", err)
+		}
+
+		addressPubKeys[i], err = btcutil.NewAddressPubKey(p.SerializeCompressed(), params)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to generate new address from pub key: %w", err)
+		}
+	}
+
+	witnessScript, err := txscript.MultiSigScript(addressPubKeys, threshold)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate multisig script: %w", err)
+	}
+
+	witnessScriptHash := sha256.Sum256(witnessScript)
+	addressWitnessScriptHash, err := btcutil.NewAddressWitnessScriptHash(witnessScriptHash[:], params)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate new address witness script hash: %w", err)
+	}
+
+	// descriptor key expressions carry the derivation path without m prefix
+	descriptorPath := strings.TrimPrefix(formatDerivationPath(indices), "m")
+	for i := range descriptorKeys {
+		descriptorKeys[i] += descriptorPath
+	}
+
+	multi := "multi"
+	if sortKeys {
+		multi = "sortedmulti"
+	}
+
+	descriptor, err := AppendChecksum(fmt.Sprintf("wsh(%s(%d,%s))", multi, threshold, strings.Join(descriptorKeys, ",")))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to append descriptor checksum: %w", err)
+	}
+
+	key := &Key{
+		Addr:            addressWitnessScriptHash.EncodeAddress(),
+		PrimaryAddress:  addressWitnessScriptHash.EncodeAddress(),
+		AddrType:        AddrTypeP2wsh,
+		DerivationPath:  formatDerivationPath(indices),
+		ResolvedIndices: indices,
+		CoinType:        CoinTypeBtc,
+		Network:         network,
+	}
+
+	return key, descriptor, nil
+}
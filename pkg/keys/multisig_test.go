@@ -0,0 +1,97 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/tyler-smith/go-bip32"
+)
+
+// 2-of-3 wsh(sortedmulti) address of account keys at m/48h/0h/{0,1,2}h/2h
+// of mnemonic abandon ... about at relative path m/0/0
+const testMultisigAddress = "bc1q2sz6vvu6k7y9gtc6kfgfe0p6xkhmvmdlu97eecjkykpdktvps08scdjgr5"
+
+func TestMultisig_SortedMulti(t *testing.T) {
+	xpubs := make([]string, 3)
+	pubKeys := make([]string, 3)
+	for i := range xpubs {
+		key, err := New(
+			&Config{
+				Seed:           TestSeed(),
+				Network:        NetworkTypeMainnet,
+				DerivationPath: fmt.Sprintf("m/48h/0h/%dh/2h", i),
+				AddrType:       AddrTypeLegacy,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		xpubs[i] = key.XPub
+
+		child, err := DeriveIndices(key.XPub, []uint32{0, 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKeys[i] = child.PubKeyHex
+	}
+
+	// OP_2 <pubkey> <pubkey> <pubkey> OP_3 OP_CHECKMULTISIG with pub keys
+	// sorted lexicographically per BIP-67
+	sort.Strings(pubKeys)
+	witnessScript, err := hex.DecodeString(
+		"52" + "21" + pubKeys[0] + "21" + pubKeys[1] + "21" + pubKeys[2] + "53ae")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	witnessScriptHash := sha256.Sum256(witnessScript)
+	expected, err := btcutil.NewAddressWitnessScriptHash(witnessScriptHash[:], &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, descriptor, err := Multisig(2, xpubs, "m/0/0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Addr != expected.EncodeAddress() || key.Addr != testMultisigAddress {
+		t.Fatal("expected", testMultisigAddress, ", got", key.Addr)
+	}
+
+	expectedDescriptor, err := AppendChecksum(
+		fmt.Sprintf("wsh(sortedmulti(2,%s/0/0,%s/0/0,%s/0/0))", xpubs[0], xpubs[1], xpubs[2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if descriptor != expectedDescriptor {
+		t.Fatal("expected", expectedDescriptor, ", got", descriptor)
+	}
+
+	// unknown key versions are rejected
+	unknown, err := bip32.B58Deserialize(xpubs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown.Version = mustDecodeHex("deadbeef")
+
+	if _, _, err := Multisig(2, []string{unknown.B58Serialize(), xpubs[1], xpubs[2]}, "m/0/0", true); err == nil {
+		t.Fatal("expected error for unknown key version")
+	}
+
+	// sorted multisig address does not depend on the order of keys
+	reordered, _, err := Multisig(2, []string{xpubs[2], xpubs[0], xpubs[1]}, "m/0/0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reordered.Addr != key.Addr {
+		t.Fatal("expected", key.Addr, "regardless of key order, got", reordered.Addr)
+	}
+}
@@ -0,0 +1,209 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/tyler-smith/go-bip32"
+)
+
+// addrNetworks is the ordered list of networks against which addresses
+// are matched. Regtest base58 addresses share prefixes with testnet and
+// are therefore reported as testnet, only bech32 regtest addresses with
+// bcrt prefix are reported as regtest. Signet shares all prefixes with
+// testnet and is reported as testnet.
+var addrNetworks = []struct {
+	network string
+	params  *chaincfg.Params
+}{
+	{network: NetworkTypeMainnet, params: &chaincfg.MainNetParams},
+	{network: NetworkTypeTestnet, params: &chaincfg.TestNet3Params},
+	{network: NetworkTypeRegtest, params: &chaincfg.RegressionNetParams},
+}
+
+// networkAliases maps common synonyms of network names
+// to the canonical network names
+var networkAliases = map[string]string{
+	"main":       NetworkTypeMainnet,
+	"prod":       NetworkTypeMainnet,
+	"production": NetworkTypeMainnet,
+	"test":       NetworkTypeTestnet,
+	"testnet3":   NetworkTypeTestnet,
+}
+
+// normalizeNetwork lower cases network and maps network aliases,
+// such as main or testnet3, to canonical network names. Unknown
+// networks are returned lower cased.
+func normalizeNetwork(network string) string {
+	network = strings.ToLower(strings.TrimSpace(network))
+	if canonical, ok := networkAliases[network]; ok {
+		return canonical
+	}
+
+	return network
+}
+
+// DetectNetwork detects the network of an extended key, a wif formatted
+// private key or an address
+func DetectNetwork(s string) (string, error) {
+	if !IsValidBase58String(s) {
+		if _, err := hex.DecodeString(s); err == nil {
+			return "", fmt.Errorf("hex encoded pub keys carry no network information")
+		}
+	}
+
+	if network, ok := extendedKeyNetwork(s); ok {
+		return network, nil
+	}
+
+	if wif, err := btcutil.DecodeWIF(s); err == nil {
+		if network, _, ok := wifNetwork(wif); ok {
+			return network, nil
+		}
+
+		return "", fmt.Errorf("detected wif network is not supported")
+	}
+
+	if network, ok := zecAddrNetwork(s); ok {
+		return network, nil
+	}
+
+	// segwit addresses are decoded locally, since btcutil cannot decode
+	// witness v1 addresses and addresses of registered coins
+	if hrp, ok := segWitHRP(s); ok {
+		if err := validateSegWitAddress(s, hrp); err != nil {
+			return "", fmt.Errorf("invalid segwit address: %w", err)
+		}
+
+		if network, ok := segWitHRPNetwork(hrp); ok {
+			return network, nil
+		}
+	}
+
+	for _, addrNetwork := range addrNetworks {
+		addr, err := btcutil.DecodeAddress(s, addrNetwork.params)
+		if err != nil {
+			continue
+		}
+
+		if addr.IsForNet(addrNetwork.params) {
+			return addrNetwork.network, nil
+		}
+	}
+
+	return "", fmt.Errorf("input is not a valid extended key, wif or address of a supported network")
+}
+
+// segWitHRPNetwork returns network of a segwit hrp of btc networks
+// or registered coins
+func segWitHRPNetwork(hrp string) (string, bool) {
+	for _, addrNetwork := range addrNetworks {
+		if addrNetwork.params.Bech32HRPSegwit == hrp {
+			return addrNetwork.network, true
+		}
+	}
+
+	for network, params := range registeredParams() {
+		for _, p := range params {
+			if p.Bech32HRPSegwit == hrp {
+				return network, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// AssertNetwork detects the network of an extended key, a wif formatted
+// private key or an address and returns an error wrapping ErrNetworkMismatch
+// if it differs from the expected network
+func AssertNetwork(keyOrAddr string, expected string) error {
+	expected = normalizeNetwork(expected)
+
+	network, err := DetectNetwork(keyOrAddr)
+	if err != nil {
+		return fmt.Errorf("failed to detect network: %w", err)
+	}
+
+	if network != expected {
+		return fmt.Errorf("%w: expected %s, found %s", ErrNetworkMismatch, expected, network)
+	}
+
+	return nil
+}
+
+// RekeyNetwork re-serializes an extended key with the key version of the
+// target network for the same script type. Key bytes, depth, parent
+// fingerprint, child number and chain code are preserved.
+func RekeyNetwork(keyString, targetNetwork string) (string, error) {
+	bip32Key, err := deserializeExtendedKey(keyString)
+	if err != nil {
+		return "", err
+	}
+
+	targetNetwork = normalizeNetwork(targetNetwork)
+	addrType := versionAddrType(bip32Key.Version)
+
+	keyType := KeyTypePub
+	if bip32Key.IsPrivate {
+		keyType = KeyTypePrv
+	}
+
+	version, ok := lookupKeyVersion(CoinTypeBtc, targetNetwork, addrType, keyType)
+	if !ok {
+		return "", fmt.Errorf("no %s key version registered for addr type %s on network %s",
+			keyType, addrType, targetNetwork)
+	}
+
+	bip32Key.Version = version
+
+	return bip32Key.B58Serialize(), nil
+}
+
+// wifNetwork returns network of a wif formatted private key by matching
+// against the ordered list of networks. Testnet and regtest share the wif
+// private key id, and testnet wins for such keys since it is listed first.
+func wifNetwork(wif *btcutil.WIF) (string, *chaincfg.Params, bool) {
+	for _, addrNetwork := range addrNetworks {
+		if wif.IsForNet(addrNetwork.params) {
+			return addrNetwork.network, addrNetwork.params, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// extendedKeyNetwork returns network of a base58 encoded extended key
+func extendedKeyNetwork(keyString string) (string, bool) {
+	if len(base58.Decode(keyString)) != 82 {
+		return "", false
+	}
+
+	key, err := bip32.B58Deserialize(keyString)
+	if err != nil {
+		return "", false
+	}
+
+	return versionNetwork(key.Version)
+}
+
+// zecAddrNetwork returns network of a zcash transparent address
+func zecAddrNetwork(addr string) (string, bool) {
+	decoded, version, err := base58.CheckDecode(addr)
+	if err != nil || len(decoded) != 21 {
+		return "", false
+	}
+
+	for network, prefix := range zecAddrPrefixes {
+		if bytes.Equal(prefix, []byte{version, decoded[0]}) {
+			return network, true
+		}
+	}
+
+	return "", false
+}
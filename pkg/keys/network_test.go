@@ -0,0 +1,146 @@
+package keys
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/tyler-smith/go-bip32"
+)
+
+func TestNormalizeNetwork(t *testing.T) {
+	tests := []struct {
+		network  string
+		expected string
+	}{
+		{"mainnet", NetworkTypeMainnet},
+		{" MainNet ", NetworkTypeMainnet},
+		{"main", NetworkTypeMainnet},
+		{"prod", NetworkTypeMainnet},
+		{"production", NetworkTypeMainnet},
+		{"testnet", NetworkTypeTestnet},
+		{"test", NetworkTypeTestnet},
+		{"TestNet3", NetworkTypeTestnet},
+		{"regtest", NetworkTypeRegtest},
+		{"bitcoin", "bitcoin"},
+		{"signet", "signet"},
+	}
+
+	for _, test := range tests {
+		if network := normalizeNetwork(test.network); network != test.expected {
+			t.Fatal(test.network, "expected", test.expected, ", got", network)
+		}
+	}
+}
+
+func TestAssertNetwork(t *testing.T) {
+	if err := AssertNetwork("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", NetworkTypeMainnet); err != nil {
+		t.Fatal(err)
+	}
+
+	err := AssertNetwork("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", NetworkTypeMainnet)
+	if !errors.Is(err, ErrNetworkMismatch) {
+		t.Fatal("expected ErrNetworkMismatch, got", err)
+	}
+}
+
+func TestDetectNetwork(t *testing.T) {
+	hash160 := mustDecodeHex("751e76e8199196d454941c45d1b3a323f1433bd6")
+	regtestAddr, err := btcutil.NewAddressWitnessPubKeyHash(hash160, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ltc, ok := LookupCoin(CoinTypeLtc)
+	if !ok {
+		t.Fatal("ltc is not registered")
+	}
+
+	ltcAddr, err := btcutil.NewAddressWitnessPubKeyHash(hash160, ltc.Params[NetworkTypeMainnet])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{testZpubAbandonAbout, NetworkTypeMainnet},
+		{"tpubD6NzVbkrYhZ4XgiXtGrdW5XDAPFCL9h7we1vwNCpn8tGbBcgfVYjXyhWo4E1xkh56hjod1RhGjxbaTLV3X4FyWuejifB9jusQ46QzG87VKp", NetworkTypeTestnet},
+		{"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", NetworkTypeMainnet},
+		{"cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA", NetworkTypeTestnet},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", NetworkTypeMainnet},
+		{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", NetworkTypeTestnet},
+		{regtestAddr.EncodeAddress(), NetworkTypeRegtest},
+		{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", NetworkTypeMainnet},
+		{ltcAddr.EncodeAddress(), NetworkTypeMainnet},
+		{"t1XVXWCvpMgBvUaed4XDqWtgQgJSu1Ghz7F", NetworkTypeMainnet},
+	}
+
+	for _, test := range tests {
+		network, err := DetectNetwork(test.input)
+		if err != nil {
+			t.Fatal(test.input, err)
+		}
+
+		if network != test.expected {
+			t.Fatal(test.input, "expected", test.expected, ", got", network)
+		}
+	}
+
+	if _, err := DetectNetwork("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"); err == nil {
+		t.Fatal("expected error for hex encoded pub key")
+	}
+}
+
+func TestRekeyNetwork(t *testing.T) {
+	master, err := bip32.NewMasterKey(TestSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, keyString := range []string{
+		master.PublicKey().B58Serialize(),
+		master.B58Serialize(),
+		testZpubAbandonAbout,
+	} {
+		testnetKey, err := RekeyNetwork(keyString, NetworkTypeTestnet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if network, err := DetectNetwork(testnetKey); err != nil || network != NetworkTypeTestnet {
+			t.Fatal("expected testnet key, got", network, err)
+		}
+
+		original, err := deserializeExtendedKey(keyString)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rekeyed, err := deserializeExtendedKey(testnetKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(original.Key, rekeyed.Key) || !bytes.Equal(original.ChainCode, rekeyed.ChainCode) ||
+			original.Depth != rekeyed.Depth {
+			t.Fatal("expected key material to be preserved")
+		}
+
+		mainnetKey, err := RekeyNetwork(testnetKey, NetworkTypeMainnet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if mainnetKey != keyString {
+			t.Fatal("expected", keyString, ", got", mainnetKey)
+		}
+	}
+
+	if _, err := RekeyNetwork(master.PublicKey().B58Serialize(), NetworkTypeRegtest); err == nil {
+		t.Fatal("expected error for network without registered key version")
+	}
+}
@@ -0,0 +1,53 @@
+package keys
+
+// Option configures key generation via NewWithOptions
+type Option func(config *Config)
+
+// WithNetwork sets the network, such as mainnet or testnet
+func WithNetwork(network string) Option {
+	return func(config *Config) {
+		config.Network = network
+	}
+}
+
+// WithScriptType sets the script type, such as legacy, p2sh or bech32
+func WithScriptType(scriptType string) Option {
+	return func(config *Config) {
+		config.AddrType = scriptType
+	}
+}
+
+// WithPath sets the derivation path, such as m/84h/0h/0h/0/0 or auto
+func WithPath(derivationPath string) Option {
+	return func(config *Config) {
+		config.DerivationPath = derivationPath
+	}
+}
+
+// WithCoin sets the coin type, such as btc or zec
+func WithCoin(coinType string) Option {
+	return func(config *Config) {
+		config.CoinType = coinType
+	}
+}
+
+// NewWithOptions generates a new key pair with a seed. By default,
+// a legacy mainnet btc key is generated at an auto derivation path,
+// which can be changed via options. Key versions are resolved per call
+// without modifying bip32 pkg globals, therefore, keys can be safely
+// generated concurrently.
+func NewWithOptions(seed []byte, opts ...Option) (*Key, error) {
+	config := &Config{
+		Seed:           seed,
+		Network:        NetworkTypeMainnet,
+		DerivationPath: "auto",
+		AddrType:       AddrTypeLegacy,
+		CoinType:       CoinTypeBtc,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return New(config)
+}
@@ -0,0 +1,58 @@
+package keys
+
+import (
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	tests := []struct {
+		opts   []Option
+		config *Config
+	}{
+		{
+			opts: nil,
+			config: &Config{
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "auto",
+				AddrType:       AddrTypeLegacy,
+				CoinType:       CoinTypeBtc,
+			},
+		},
+		{
+			opts: []Option{WithNetwork(NetworkTypeTestnet), WithScriptType(AddrTypeP2wpkh), WithPath("m/84h/1h/0h/0/1")},
+			config: &Config{
+				Network:        NetworkTypeTestnet,
+				DerivationPath: "m/84h/1h/0h/0/1",
+				AddrType:       AddrTypeP2wpkh,
+				CoinType:       CoinTypeBtc,
+			},
+		},
+		{
+			opts: []Option{WithCoin(CoinTypeLtc), WithScriptType(AddrTypeP2wpkhP2sh)},
+			config: &Config{
+				Network:        NetworkTypeMainnet,
+				DerivationPath: "auto",
+				AddrType:       AddrTypeP2wpkhP2sh,
+				CoinType:       CoinTypeLtc,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		key, err := NewWithOptions(TestSeed(), test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		test.config.Seed = TestSeed()
+		expected, err := New(test.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if key.XPrv != expected.XPrv || key.Addr != expected.Addr ||
+			key.DerivationPath != expected.DerivationPath || key.CoinType != expected.CoinType {
+			t.Fatal("expected", expected.DerivationPath, expected.Addr, ", got", key.DerivationPath, key.Addr)
+		}
+	}
+}
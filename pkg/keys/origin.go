@@ -0,0 +1,85 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/kubetrail/bip39/pkg/mnemonics"
+	"github.com/tyler-smith/go-bip32"
+)
+
+// KeyOrigin is the key origin of a derived key as required by BIP-174
+// PSBT key paths, i.e., the master key fingerprint and child indices
+// from the master key, with hardened indices offset by bip32.FirstHardenedChild
+type KeyOrigin struct {
+	MasterFingerprint [4]byte  `json:"masterFingerprint" yaml:"masterFingerprint"`
+	Path              []uint32 `json:"path" yaml:"path"`
+}
+
+// KeyOrigin returns key origin of the key for the fingerprint of the master
+// key it was derived from. The derivation path of the key must be relative
+// to the master key, as is the case for keys generated via New.
+func (k *Key) KeyOrigin(masterFingerprint [4]byte) (*KeyOrigin, error) {
+	indices := k.ResolvedIndices
+	if len(indices) == 0 && len(k.DerivationPath) > 0 {
+		var err error
+		indices, err = parseDerivationPath(k.DerivationPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse derivation path: %w", err)
+		}
+	}
+
+	return &KeyOrigin{
+		MasterFingerprint: masterFingerprint,
+		Path:              append([]uint32{}, indices...),
+	}, nil
+}
+
+// OriginPrefix formats the key origin of an output descriptor key
+// expression, such as [73c5da0a/84h/0h/0h], from the hex encoded master
+// fingerprint and the derivation path relative to the master key.
+// Hardened indices are formatted using HardenedMarkerH.
+func OriginPrefix(masterFingerprint string, path string) (string, error) {
+	fingerprint, err := hex.DecodeString(masterFingerprint)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode master fingerprint: %w", err)
+	}
+
+	if len(fingerprint) != 4 {
+		return "", fmt.Errorf("master fingerprint must be 4 bytes long, found %d bytes", len(fingerprint))
+	}
+
+	indices, err := parseDerivationPath(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse derivation path: %w", err)
+	}
+
+	return fmt.Sprintf("[%s%s]",
+		hex.EncodeToString(fingerprint),
+		strings.TrimPrefix(formatDerivationPath(indices), "m")), nil
+}
+
+// MasterFingerprint returns hex encoded fingerprint of the master key of
+// the seed, i.e., the first four bytes of hash160 of its pub key, which
+// identifies the seed across wallets and devices
+func MasterFingerprint(seed []byte) (string, error) {
+	masterKey, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate root key: %w", err)
+	}
+
+	return hex.EncodeToString(btcutil.Hash160(masterKey.PublicKey().Key)[:4]), nil
+}
+
+// MasterFingerprintFromMnemonic returns hex encoded fingerprint of the
+// master key of the seed of an English BIP-39 mnemonic and passphrase
+func MasterFingerprintFromMnemonic(mnemonic, passphrase string) (string, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase, mnemonics.LanguageEnglish)
+	if err != nil {
+		return "", err
+	}
+
+	return MasterFingerprint(seed)
+}
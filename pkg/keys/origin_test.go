@@ -0,0 +1,98 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestOriginPrefix(t *testing.T) {
+	tests := []struct {
+		fingerprint string
+		path        string
+		expected    string
+	}{
+		{"73c5da0a", "m/84h/0h/0h", "[73c5da0a/84h/0h/0h]"},
+		{"73C5DA0A", "m/84'/0'/0'", "[73c5da0a/84h/0h/0h]"},
+		{"73c5da0a", "m/48H/1H/0H/2H", "[73c5da0a/48h/1h/0h/2h]"},
+		{"73c5da0a", "m/0/1", "[73c5da0a/0/1]"},
+		{"73c5da0a", "m", "[73c5da0a]"},
+	}
+
+	for _, test := range tests {
+		prefix, err := OriginPrefix(test.fingerprint, test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if prefix != test.expected {
+			t.Fatal("expected", test.expected, ", got", prefix)
+		}
+	}
+
+	for _, fingerprint := range []string{"", "73c5da", "73c5da0a00", "zzc5da0a"} {
+		if _, err := OriginPrefix(fingerprint, "m/84h"); err == nil {
+			t.Fatal("expected error for master fingerprint", fingerprint)
+		}
+	}
+}
+
+func TestKey_PSBTInputMeta(t *testing.T) {
+	tests := []struct {
+		addrType     string
+		path         string
+		scriptType   string
+		redeemScript bool
+	}{
+		{AddrTypeLegacy, "m/44h/0h/0h/0/0", AddrTypeP2pkhOrP2sh, false},
+		{AddrTypeSegWitCompatible, "m/49h/0h/0h/0/0", AddrTypeP2wpkhP2sh, true},
+		{AddrTypeSegWitNative, "m/84h/0h/0h/0/0", AddrTypeP2wpkh, false},
+	}
+
+	fingerprint := [4]byte{0x73, 0xc5, 0xda, 0x0a}
+	for _, test := range tests {
+		key, err := New(
+			&Config{
+				Seed:           TestSeed(),
+				Network:        NetworkTypeMainnet,
+				DerivationPath: test.path,
+				AddrType:       test.addrType,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		meta, err := key.PSBTInputMeta(fingerprint)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if meta.ScriptType != test.scriptType {
+			t.Fatal("expected script type", test.scriptType, ", got", meta.ScriptType)
+		}
+
+		if meta.MasterFingerprint != fingerprint || len(meta.Path) != 5 {
+			t.Fatal("unexpected key origin for", test.path)
+		}
+
+		if hex.EncodeToString(meta.PubKey) != key.PubKeyHex {
+			t.Fatal("expected pub key", key.PubKeyHex, ", got", hex.EncodeToString(meta.PubKey))
+		}
+
+		if (len(meta.RedeemScript) > 0) != test.redeemScript {
+			t.Fatal("unexpected redeem script for", test.addrType)
+		}
+	}
+}
+
+func TestMasterFingerprintFromMnemonic(t *testing.T) {
+	fingerprint, err := MasterFingerprintFromMnemonic(
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fingerprint != "73c5da0a" {
+		t.Fatal("expected 73c5da0a, got", fingerprint)
+	}
+}
@@ -0,0 +1,72 @@
+package keys
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip32"
+)
+
+// PathIterator expands a derivation path template with a single trailing
+// wildcard, such as m/84h/0h/0h/0/*, over a range of indices
+type PathIterator struct {
+	indices []uint32
+	offset  uint32
+	next    uint64
+	end     uint64
+}
+
+// NewPathIterator creates a new PathIterator for the template yielding
+// paths for indices in start:end, end being exclusive. Trailing wildcard
+// can be hardened, i.e., *h or *'.
+func NewPathIterator(template string, start, end uint32) (*PathIterator, error) {
+	template = strings.Trim(strings.TrimSpace(template), "/")
+
+	var offset uint32
+	switch {
+	case strings.HasSuffix(template, "/*"):
+		template = strings.TrimSuffix(template, "/*")
+	case strings.HasSuffix(template, "/*h"), strings.HasSuffix(template, "/*'"):
+		template = template[:len(template)-3]
+		offset = bip32.FirstHardenedChild
+	default:
+		return nil, fmt.Errorf("derivation path template must end with a wildcard: %s", template)
+	}
+
+	if strings.Contains(template, "*") {
+		return nil, fmt.Errorf("only a single trailing wildcard is allowed: %s", template)
+	}
+
+	indices, err := parseDerivationPath(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse derivation path template: %w", err)
+	}
+
+	if start > end {
+		return nil, fmt.Errorf("invalid range %d:%d, start must not exceed end", start, end)
+	}
+
+	if end > bip32.FirstHardenedChild {
+		return nil, fmt.Errorf("invalid range %d:%d, end must not exceed %d", start, end, bip32.FirstHardenedChild)
+	}
+
+	return &PathIterator{
+		indices: indices,
+		offset:  offset,
+		next:    uint64(start),
+		end:     uint64(end),
+	}, nil
+}
+
+// Next returns the next derivation path and true, or an empty
+// string and false once the range is exhausted
+func (p *PathIterator) Next() (string, bool) {
+	if p.next >= p.end {
+		return "", false
+	}
+
+	indices := append(append(make([]uint32, 0, len(p.indices)+1), p.indices...), uint32(p.next)+p.offset)
+	p.next++
+
+	return formatDerivationPath(indices), true
+}
@@ -0,0 +1,61 @@
+package keys
+
+import (
+	"testing"
+)
+
+func TestPathIterator(t *testing.T) {
+	tests := []struct {
+		template   string
+		start, end uint32
+		expected   []string
+	}{
+		{"m/84h/0h/0h/0/*", 3, 6, []string{"m/84h/0h/0h/0/3", "m/84h/0h/0h/0/4", "m/84h/0h/0h/0/5"}},
+		{"m/84'/0'/0'/*h", 0, 2, []string{"m/84h/0h/0h/0h", "m/84h/0h/0h/1h"}},
+		{"m/*'", 1, 2, []string{"m/1h"}},
+		{"m/0/*", 5, 5, nil},
+	}
+
+	for _, test := range tests {
+		iterator, err := NewPathIterator(test.template, test.start, test.end)
+		if err != nil {
+			t.Fatal(test.template, err)
+		}
+
+		var paths []string
+		for path, ok := iterator.Next(); ok; path, ok = iterator.Next() {
+			paths = append(paths, path)
+		}
+
+		if len(paths) != len(test.expected) {
+			t.Fatal(test.template, "expected", len(test.expected), "paths, got", len(paths))
+		}
+
+		for i := range paths {
+			if paths[i] != test.expected[i] {
+				t.Fatal(test.template, "expected", test.expected[i], ", got", paths[i])
+			}
+		}
+
+		// iterator remains exhausted at end of range
+		if path, ok := iterator.Next(); ok || len(path) != 0 {
+			t.Fatal(test.template, "expected exhausted iterator, got", path)
+		}
+	}
+
+	invalid := []struct {
+		template   string
+		start, end uint32
+	}{
+		{"m/0/1", 0, 1},
+		{"m/*/0/*", 0, 1},
+		{"m/0/*", 2, 1},
+		{"m/0/*", 0, 1<<31 + 1},
+	}
+
+	for _, test := range invalid {
+		if _, err := NewPathIterator(test.template, test.start, test.end); err == nil {
+			t.Fatal("expected error for", test.template, test.start, test.end)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package keys
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// PSBTInputMeta is derivation metadata of a key required to construct
+// a BIP-174 PSBT input spending an output of its single key address.
+// RedeemScript is set only for nested segwit p2sh-p2wpkh keys and
+// TapInternalKey only for taproot keys.
+// https://github.com/bitcoin/bips/blob/master/bip-0174.mediawiki
+type PSBTInputMeta struct {
+	PubKey            []byte   `json:"pubKey" yaml:"pubKey"`
+	MasterFingerprint [4]byte  `json:"masterFingerprint" yaml:"masterFingerprint"`
+	Path              []uint32 `json:"path" yaml:"path"`
+	ScriptType        string   `json:"scriptType" yaml:"scriptType"`
+	RedeemScript      []byte   `json:"redeemScript,omitempty" yaml:"redeemScript,omitempty"`
+	TapInternalKey    []byte   `json:"tapInternalKey,omitempty" yaml:"tapInternalKey,omitempty"`
+}
+
+// PSBTInputMeta returns PSBT input metadata of the key for the fingerprint
+// of the master key it was derived from. Script type is taken from the
+// address type of the key and multisig script types are rejected since
+// they have no single key script.
+func (k *Key) PSBTInputMeta(masterFingerprint [4]byte) (*PSBTInputMeta, error) {
+	pubKeyBytes, err := k.PubKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pubkey: %w", err)
+	}
+
+	origin, err := k.KeyOrigin(masterFingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &PSBTInputMeta{
+		PubKey:            pub.SerializeCompressed(),
+		MasterFingerprint: origin.MasterFingerprint,
+		Path:              origin.Path,
+		ScriptType:        keyScriptType(k),
+	}
+
+	switch meta.ScriptType {
+	case AddrTypeP2pkhOrP2sh, AddrTypeP2wpkh:
+	case AddrTypeP2wpkhP2sh:
+		meta.RedeemScript, err = RedeemScriptP2SHP2WPKH(k)
+		if err != nil {
+			return nil, err
+		}
+	case AddrTypeP2tr:
+		meta.TapInternalKey, _, err = taprootKeys(meta.PubKey)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("no single key script exists for addr type %s", k.AddrType)
+	}
+
+	return meta, nil
+}
+
+// keyScriptType returns normalized script type of a key from its addr
+// type, which may be formatted for output, such as segwit-native, bech32
+func keyScriptType(k *Key) string {
+	addrType := strings.TrimSpace(strings.Split(k.AddrType, ",")[0])
+	return normalizeAddrType(strings.ToLower(addrType))
+}
@@ -0,0 +1,80 @@
+package keys
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+)
+
+// RawMaterial is raw key material of a key along with its hex and base64
+// encodings. Private key and chain code are empty when not available, such
+// as private key of a public key or chain code of a wif key.
+type RawMaterial struct {
+	PrivKeyBytes    []byte `json:"-" yaml:"-"`
+	PubKeyBytes     []byte `json:"-" yaml:"-"`
+	ChainCodeBytes  []byte `json:"-" yaml:"-"`
+	PrivKeyHex      string `json:"privKeyHex,omitempty" yaml:"privKeyHex,omitempty"`
+	PrivKeyBase64   string `json:"privKeyBase64,omitempty" yaml:"privKeyBase64,omitempty"`
+	PubKeyHex       string `json:"pubKeyHex,omitempty" yaml:"pubKeyHex,omitempty"`
+	PubKeyBase64    string `json:"pubKeyBase64,omitempty" yaml:"pubKeyBase64,omitempty"`
+	ChainCodeHex    string `json:"chainCodeHex,omitempty" yaml:"chainCodeHex,omitempty"`
+	ChainCodeBase64 string `json:"chainCodeBase64,omitempty" yaml:"chainCodeBase64,omitempty"`
+}
+
+// RawMaterial returns 32 byte private key, serialized pub key and
+// 32 byte chain code of the key as raw bytes, hex and base64 strings
+func (k *Key) RawMaterial() (*RawMaterial, error) {
+	raw := &RawMaterial{}
+
+	switch {
+	case len(k.XPrv) > 0:
+		prvKey, err := PrivKey(k.XPrv)
+		if err != nil {
+			return nil, err
+		}
+		raw.PrivKeyBytes = prvKey.Serialize()
+	case len(k.PrvKeyWif) > 0:
+		wif, err := btcutil.DecodeWIF(k.PrvKeyWif)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode wif: %w", err)
+		}
+		raw.PrivKeyBytes = wif.PrivKey.Serialize()
+	}
+
+	pubKeyBytes, err := k.PubKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+	raw.PubKeyBytes = pubKeyBytes
+
+	for _, keyString := range []string{k.XPub, k.XPrv} {
+		if len(keyString) == 0 {
+			continue
+		}
+
+		chainCode, err := ChainCode(keyString)
+		if err != nil {
+			return nil, err
+		}
+		raw.ChainCodeBytes = chainCode
+		break
+	}
+
+	raw.PrivKeyHex, raw.PrivKeyBase64 = hexAndBase64(raw.PrivKeyBytes)
+	raw.PubKeyHex, raw.PubKeyBase64 = hexAndBase64(raw.PubKeyBytes)
+	raw.ChainCodeHex, raw.ChainCodeBase64 = hexAndBase64(raw.ChainCodeBytes)
+
+	return raw, nil
+}
+
+// hexAndBase64 encodes b as hex and standard base64, returning
+// empty strings for empty input
+func hexAndBase64(b []byte) (string, string) {
+	if len(b) == 0 {
+		return "", ""
+	}
+
+	return hex.EncodeToString(b), base64.StdEncoding.EncodeToString(b)
+}
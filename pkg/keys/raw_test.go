@@ -0,0 +1,50 @@
+package keys
+
+import (
+	"testing"
+)
+
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+func TestKey_RawMaterial(t *testing.T) {
+	key, err := New(
+		&Config{
+			SeedHex:        TestVector1Seed,
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m",
+			AddrType:       AddrTypeLegacy,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := key.RawMaterial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &RawMaterial{
+		PrivKeyHex:      "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		PrivKeyBase64:   "6PMucj3s9AUa76yOLJPJxbIUMTgXzbAaFJS5F8hDazU=",
+		PubKeyHex:       "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2",
+		PubKeyBase64:    "AzmjYBMwFZfa70H75ZOgLMUT0LVVJ+wt8QUOLo/0nIXC",
+		ChainCodeHex:    "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+		ChainCodeBase64: "hz3/gcAvUlYj/R/lFn6sOlWgSd49MUu0LuIn/+031Qg=",
+	}
+
+	if raw.PrivKeyHex != expected.PrivKeyHex || raw.PrivKeyBase64 != expected.PrivKeyBase64 ||
+		raw.PubKeyHex != expected.PubKeyHex || raw.PubKeyBase64 != expected.PubKeyBase64 ||
+		raw.ChainCodeHex != expected.ChainCodeHex || raw.ChainCodeBase64 != expected.ChainCodeBase64 {
+		t.Fatalf("expected %+v, got %+v", expected, raw)
+	}
+
+	// pub key carries no private key
+	raw, err = (&Key{XPub: key.XPub, PubKeyHex: key.PubKeyHex}).RawMaterial()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(raw.PrivKeyBytes) != 0 || raw.ChainCodeHex != expected.ChainCodeHex {
+		t.Fatalf("expected no private key and chain code %s, got %+v", expected.ChainCodeHex, raw)
+	}
+}
@@ -0,0 +1,205 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+)
+
+// Schnorr signing in this file is not constant-time. Secret scalars go
+// through math/big and btcec v1 curve operations, both of which leak timing
+// information about private keys. It is therefore kept unexported and must
+// not be exposed until it can be built on btcec/v2 schnorr package.
+
+// signSchnorrKeyPath signs a 32 byte message hash, such as a BIP-341 signature
+// hash, with BIP-340 schnorr signature scheme using private key of the key
+// tweaked per BIP-86 for key path spends of taproot outputs without a script
+// tree. Signature verifies against the taproot output key of the key. Keys
+// without private key material are rejected.
+// https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+func signSchnorrKeyPath(k *Key, msgHash []byte) ([]byte, error) {
+	if len(msgHash) != 32 {
+		return nil, fmt.Errorf("message hash must be 32 bytes long, found %d bytes", len(msgHash))
+	}
+
+	var prvKeyBytes []byte
+	switch {
+	case len(k.XPrv) > 0:
+		prvKey, err := PrivKey(k.XPrv)
+		if err != nil {
+			return nil, err
+		}
+		prvKeyBytes = prvKey.Serialize()
+	case len(k.PrvKeyWif) > 0:
+		wif, err := btcutil.DecodeWIF(k.PrvKeyWif)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode wif: %w", err)
+		}
+		prvKeyBytes = wif.PrivKey.Serialize()
+	default:
+		return nil, fmt.Errorf("key has no private key, schnorr signing requires a private key")
+	}
+
+	tweakedKey, err := taprootTweakPrivKey(prvKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	auxRand := make([]byte, 32)
+	if _, err := rand.Read(auxRand); err != nil {
+		return nil, fmt.Errorf("failed to read auxiliary randomness: %w", err)
+	}
+
+	return signSchnorr(tweakedKey, msgHash, auxRand)
+}
+
+// taprootTweakPrivKey tweaks private key with BIP-86 taproot tweak
+// of its x-only internal key
+// https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#constructing-and-spending-taproot-outputs
+func taprootTweakPrivKey(prvKeyBytes []byte) ([]byte, error) {
+	curve := btcec.S256()
+
+	d := new(big.Int).SetBytes(prvKeyBytes)
+	if d.Sign() == 0 || d.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("private key is not in 1:n-1")
+	}
+
+	px, py := curve.ScalarBaseMult(prvKeyBytes)
+	if py.Bit(0) == 1 {
+		d.Sub(curve.N, d)
+	}
+
+	tweak := new(big.Int).SetBytes(taggedHash("TapTweak", xOnlyBytes(px)))
+	if tweak.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("invalid taproot tweak")
+	}
+
+	d.Add(d, tweak).Mod(d, curve.N)
+	if d.Sign() == 0 {
+		return nil, fmt.Errorf("tweaked private key is zero")
+	}
+
+	return xOnlyBytes(d), nil
+}
+
+// signSchnorr signs a 32 byte message with BIP-340 schnorr signature
+// scheme using auxiliary randomness and verifies the signature
+func signSchnorr(prvKeyBytes, msg, auxRand []byte) ([]byte, error) {
+	curve := btcec.S256()
+
+	d := new(big.Int).SetBytes(prvKeyBytes)
+	if d.Sign() == 0 || d.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("private key is not in 1:n-1")
+	}
+
+	px, py := curve.ScalarBaseMult(xOnlyBytes(d))
+	if py.Bit(0) == 1 {
+		d.Sub(curve.N, d)
+	}
+	pubKey := xOnlyBytes(px)
+
+	t := xOnlyBytes(d)
+	for i, b := range taggedHash("BIP0340/aux", auxRand) {
+		t[i] ^= b
+	}
+
+	k := new(big.Int).SetBytes(taggedHash("BIP0340/nonce", t, pubKey, msg))
+	k.Mod(k, curve.N)
+	if k.Sign() == 0 {
+		return nil, fmt.Errorf("invalid schnorr nonce")
+	}
+
+	rx, ry := curve.ScalarBaseMult(xOnlyBytes(k))
+	if ry.Bit(0) == 1 {
+		k.Sub(curve.N, k)
+	}
+	r := xOnlyBytes(rx)
+
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", r, pubKey, msg))
+	e.Mod(e, curve.N)
+
+	s := new(big.Int).Mul(e, d)
+	s.Add(s, k).Mod(s, curve.N)
+
+	sig := append(r, xOnlyBytes(s)...)
+	if !verifySchnorr(pubKey, msg, sig) {
+		return nil, fmt.Errorf("failed to verify schnorr signature")
+	}
+
+	return sig, nil
+}
+
+// verifySchnorr verifies BIP-340 schnorr signature of a 32 byte
+// message against x-only pub key
+func verifySchnorr(pubKey, msg, sig []byte) bool {
+	curve := btcec.S256()
+
+	if len(pubKey) != 32 || len(sig) != 64 {
+		return false
+	}
+
+	px := new(big.Int).SetBytes(pubKey)
+	py, ok := liftX(px)
+	if !ok {
+		return false
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(curve.P) >= 0 || s.Cmp(curve.N) >= 0 {
+		return false
+	}
+
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", sig[:32], pubKey, msg))
+	e.Mod(e, curve.N)
+
+	// R = s*G - e*P
+	sx, sy := curve.ScalarBaseMult(xOnlyBytes(s))
+	ex, ey := curve.ScalarMult(px, py, xOnlyBytes(e))
+	ey.Sub(curve.P, ey)
+	rx, ry := curve.Add(sx, sy, ex, ey)
+
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return false
+	}
+
+	return ry.Bit(0) == 0 && bytes.Equal(xOnlyBytes(rx), sig[:32])
+}
+
+// liftX returns the even y coordinate of the curve point with x coordinate
+func liftX(x *big.Int) (*big.Int, bool) {
+	curve := btcec.S256()
+	if x.Cmp(curve.P) >= 0 {
+		return nil, false
+	}
+
+	// y^2 = x^3 + 7
+	c := new(big.Int).Exp(x, big.NewInt(3), curve.P)
+	c.Add(c, big.NewInt(7)).Mod(c, curve.P)
+
+	// p = 3 mod 4, hence sqrt(c) = c^((p+1)/4)
+	exp := new(big.Int).Add(curve.P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(c, exp, curve.P)
+
+	if new(big.Int).Exp(y, big.NewInt(2), curve.P).Cmp(c) != 0 {
+		return nil, false
+	}
+
+	if y.Bit(0) == 1 {
+		y.Sub(curve.P, y)
+	}
+
+	return y, true
+}
+
+// xOnlyBytes serializes a scalar or x coordinate as 32 bytes
+func xOnlyBytes(x *big.Int) []byte {
+	b := make([]byte, 32)
+	x.FillBytes(b)
+	return b
+}
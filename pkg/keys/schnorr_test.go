@@ -0,0 +1,98 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// https://github.com/bitcoin/bips/blob/master/bip-0340/test-vectors.csv
+func TestSignSchnorr_BIP340(t *testing.T) {
+	tests := []struct {
+		prvKey  string
+		pubKey  string
+		auxRand string
+		msg     string
+		sig     string
+	}{
+		{
+			prvKey:  "0000000000000000000000000000000000000000000000000000000000000003",
+			pubKey:  "F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			auxRand: "0000000000000000000000000000000000000000000000000000000000000000",
+			msg:     "0000000000000000000000000000000000000000000000000000000000000000",
+			sig: "E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA8215" +
+				"25F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+		},
+		{
+			prvKey:  "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+			pubKey:  "DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+			auxRand: "0000000000000000000000000000000000000000000000000000000000000001",
+			msg:     "243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+			sig: "6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE3341" +
+				"8906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+		},
+	}
+
+	for _, test := range tests {
+		prvKey, _ := hex.DecodeString(test.prvKey)
+		pubKey, _ := hex.DecodeString(test.pubKey)
+		auxRand, _ := hex.DecodeString(test.auxRand)
+		msg, _ := hex.DecodeString(test.msg)
+
+		sig, err := signSchnorr(prvKey, msg, auxRand)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.ToUpper(hex.EncodeToString(sig)) != test.sig {
+			t.Fatal("expected", test.sig, ", got", strings.ToUpper(hex.EncodeToString(sig)))
+		}
+
+		if !verifySchnorr(pubKey, msg, sig) {
+			t.Fatal("failed to verify signature against pub key", test.pubKey)
+		}
+	}
+}
+
+func TestSignSchnorr_Taproot(t *testing.T) {
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/86h/0h/0h/0/0",
+			AddrType:       AddrTypeLegacy,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgHash := make([]byte, 32)
+	sig, err := signSchnorrKeyPath(key, msgHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taprootKeys, err := key.TaprootKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputKey, err := hex.DecodeString(taprootKeys.OutputKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !verifySchnorr(outputKey, msgHash, sig) {
+		t.Fatal("failed to verify signature against taproot output key")
+	}
+
+	pubKey, err := DecodePublicHex(key.PubKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := signSchnorrKeyPath(pubKey, msgHash); err == nil {
+		t.Fatal("expected error signing with public key")
+	}
+}
@@ -0,0 +1,17 @@
+package keys
+
+// TestVector1Seed is the hex encoded seed of BIP-32 test vector 1
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-1
+const TestVector1Seed = "000102030405060708090a0b0c0d0e0f"
+
+// testSeed is the seed for mnemonic with no passphrase:
+// abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about
+const testSeed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+// TestSeed returns the well known seed of mnemonic "abandon ... about",
+// which is used by test vectors of BIP-44, BIP-49, BIP-84 and BIP-86.
+// It is meant for reproducible examples and tests and must never be
+// used to hold funds.
+func TestSeed() []byte {
+	return mustDecodeHex(testSeed)
+}
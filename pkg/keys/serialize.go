@@ -0,0 +1,117 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/tyler-smith/go-bip32"
+)
+
+// SerializeXPub re-serializes extended pub key of the key from its structural
+// fields, i.e., version, depth, parent fingerprint, child number, chain code
+// and key bytes, computing a fresh checksum. XPub may be provided with or
+// without its trailing 4 byte checksum, which allows keys edited or
+// constructed by hand to be serialized to a valid base58check string.
+// XPrv is neutered when XPub is empty.
+func (k *Key) SerializeXPub() (string, error) {
+	if len(k.XPub) == 0 && len(k.XPrv) > 0 {
+		bip32Key, err := structuralExtendedKey(k.XPrv, true)
+		if err != nil {
+			return "", err
+		}
+
+		pubVersion, ok := lookupVersions(bip32Key.Version)
+		if !ok {
+			return "", fmt.Errorf("failed to identity valid key version")
+		}
+
+		pubKey := bip32Key.PublicKey()
+		pubKey.Version = mustDecodeHex(pubVersion[0])
+
+		return pubKey.B58Serialize(), nil
+	}
+
+	bip32Key, err := structuralExtendedKey(k.XPub, false)
+	if err != nil {
+		return "", err
+	}
+
+	return bip32Key.B58Serialize(), nil
+}
+
+// SerializeXPrv re-serializes extended private key of the key from its
+// structural fields computing a fresh checksum. See SerializeXPub.
+func (k *Key) SerializeXPrv() (string, error) {
+	bip32Key, err := structuralExtendedKey(k.XPrv, true)
+	if err != nil {
+		return "", err
+	}
+
+	return bip32Key.B58Serialize(), nil
+}
+
+// structuralExtendedKey decodes structural fields of a base58 encoded
+// extended key ignoring its checksum, if any
+func structuralExtendedKey(keyString string, isPrivate bool) (*bip32.Key, error) {
+	if len(keyString) == 0 {
+		return nil, fmt.Errorf("key has no extended key to serialize")
+	}
+
+	if err := checkInputLength(keyString); err != nil {
+		return nil, err
+	}
+
+	data := base58.Decode(keyString)
+	switch len(data) {
+	case 78, 82:
+		data = data[:78]
+	default:
+		return nil, fmt.Errorf("extended key must be 78 bytes long without or 82 bytes long with checksum, found %d bytes",
+			len(data))
+	}
+
+	key := &bip32.Key{
+		Version:     data[0:4],
+		Depth:       data[4],
+		FingerPrint: data[5:9],
+		ChildNumber: data[9:13],
+		ChainCode:   data[13:45],
+		IsPrivate:   data[45] == 0,
+	}
+
+	if key.IsPrivate {
+		key.Key = data[46:78]
+	} else {
+		key.Key = data[45:78]
+	}
+
+	if err := ValidateKeyPrefix(key.IsPrivate, data[45]); err != nil {
+		return nil, err
+	}
+
+	if isPrivate && !key.IsPrivate {
+		return nil, fmt.Errorf("expected extended private key, found extended pub key")
+	}
+
+	if !isPrivate && key.IsPrivate {
+		return nil, fmt.Errorf("expected extended pub key, found extended private key")
+	}
+
+	versions, ok := lookupVersions(key.Version)
+	if !ok {
+		return nil, fmt.Errorf("failed to identity valid key version")
+	}
+
+	// versions are listed as pub and prv key versions
+	expected := versions[0]
+	if isPrivate {
+		expected = versions[1]
+	}
+
+	if expected != hex.EncodeToString(key.Version) {
+		return nil, fmt.Errorf("key version %x does not match key type", key.Version)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,68 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+func TestKey_SerializeXPubXPrv(t *testing.T) {
+	key, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/84h/0h/0h",
+			AddrType:       AddrTypeP2wpkh,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// strip or corrupt the trailing checksum
+	edit := func(keyString string, corrupt bool) string {
+		data := base58.Decode(keyString)
+		if !corrupt {
+			return base58.Encode(data[:78])
+		}
+
+		data[len(data)-1] ^= 0xff
+		return base58.Encode(data)
+	}
+
+	for _, corrupt := range []bool{false, true} {
+		edited := &Key{XPub: edit(key.XPub, corrupt), XPrv: edit(key.XPrv, corrupt)}
+
+		xpub, err := edited.SerializeXPub()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if xpub != key.XPub {
+			t.Fatal("expected", key.XPub, ", got", xpub)
+		}
+
+		xprv, err := edited.SerializeXPrv()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if xprv != key.XPrv {
+			t.Fatal("expected", key.XPrv, ", got", xprv)
+		}
+	}
+
+	// xprv is neutered when there is no xpub
+	xpub, err := (&Key{XPrv: key.XPrv}).SerializeXPub()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if xpub != key.XPub {
+		t.Fatal("expected", key.XPub, ", got", xpub)
+	}
+
+	if _, err := (&Key{XPrv: key.XPub}).SerializeXPrv(); err == nil {
+		t.Fatal("expected error for serializing xpub as xprv")
+	}
+}
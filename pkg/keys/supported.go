@@ -0,0 +1,35 @@
+package keys
+
+import (
+	"sort"
+)
+
+// SupportedNetworks returns networks accepted by New
+func SupportedNetworks() []string {
+	return []string{NetworkTypeMainnet, NetworkTypeTestnet}
+}
+
+// SupportedScriptTypes returns canonical script types accepted by New for
+// any of the supported coins. Aliases, such as legacy or bech32, are also
+// accepted by New and map to these script types.
+func SupportedScriptTypes() []string {
+	// taproot keys use xpub key versions and have no key versions of their own
+	seen := map[string]struct{}{AddrTypeP2tr: {}}
+	for _, entry := range keyVersionEntries() {
+		seen[entry.addrType] = struct{}{}
+	}
+
+	scriptTypes := make([]string, 0, len(seen))
+	for scriptType := range seen {
+		scriptTypes = append(scriptTypes, scriptType)
+	}
+	sort.Strings(scriptTypes)
+
+	return scriptTypes
+}
+
+// SupportedCoins returns coin types accepted by New including
+// coins registered via RegisterCoin
+func SupportedCoins() []string {
+	return coinTypes()
+}
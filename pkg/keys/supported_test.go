@@ -0,0 +1,54 @@
+package keys
+
+import (
+	"testing"
+)
+
+func TestSupported_RoundTrip(t *testing.T) {
+	for _, network := range SupportedNetworks() {
+		if _, err := New(&Config{
+			Seed:           TestSeed(),
+			Network:        network,
+			DerivationPath: "auto",
+			AddrType:       AddrTypeLegacy,
+		}); err != nil {
+			t.Fatal(network, err)
+		}
+	}
+
+	for _, scriptType := range SupportedScriptTypes() {
+		for _, network := range SupportedNetworks() {
+			if _, err := New(&Config{
+				Seed:           TestSeed(),
+				Network:        network,
+				DerivationPath: "auto",
+				AddrType:       scriptType,
+			}); err != nil {
+				t.Fatal(scriptType, network, err)
+			}
+		}
+	}
+
+	for _, coinType := range SupportedCoins() {
+		config := &Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "auto",
+			AddrType:       AddrTypeLegacy,
+			CoinType:       coinType,
+		}
+
+		if err := config.Validate(); err != nil {
+			t.Fatal(coinType, err)
+		}
+
+		key, err := New(config)
+		if err != nil {
+			t.Fatal(coinType, err)
+		}
+
+		if key.CoinType != coinType {
+			t.Fatal("expected coin type", coinType, ", got", key.CoinType)
+		}
+	}
+}
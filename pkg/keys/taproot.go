@@ -0,0 +1,163 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// bech32mConst is the checksum constant for bech32m encoding
+// https://github.com/bitcoin/bips/blob/master/bip-0350.mediawiki
+const bech32mConst = 0x2bc830a3
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// taggedHash implements BIP-340 tagged hash
+// https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+func taggedHash(tag string, msgs ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, msg := range msgs {
+		h.Write(msg)
+	}
+	return h.Sum(nil)
+}
+
+// TaprootKeys are the x-only internal key and the tweaked x-only output key
+// of a key path only taproot spend. Signers sign with the private key tweaked
+// the same way as the output key, whereas the output key is the one committed
+// to in the p2tr output script and address.
+type TaprootKeys struct {
+	InternalKey string `json:"taprootInternalKey,omitempty" yaml:"taprootInternalKey,omitempty"`
+	OutputKey   string `json:"taprootOutputKey,omitempty" yaml:"taprootOutputKey,omitempty"`
+}
+
+// TaprootKeys returns hex encoded internal and output keys of the pub key
+// of the key per BIP-86
+func (k *Key) TaprootKeys() (*TaprootKeys, error) {
+	pubKeyBytes, err := k.PubKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	internalKey, outputKey, err := taprootKeys(pubKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaprootKeys{
+		InternalKey: hex.EncodeToString(internalKey),
+		OutputKey:   hex.EncodeToString(outputKey),
+	}, nil
+}
+
+// taprootOutputKey computes x-only taproot output key for a key path
+// only spend, i.e., without a script tree, per BIP-86
+// https://github.com/bitcoin/bips/blob/master/bip-0086.mediawiki
+func taprootOutputKey(serializedPubKey []byte) ([]byte, error) {
+	_, outputKey, err := taprootKeys(serializedPubKey)
+	return outputKey, err
+}
+
+// taprootKeys computes x-only untweaked internal key and tweaked
+// output key for a key path only spend
+func taprootKeys(serializedPubKey []byte) ([]byte, []byte, error) {
+	pub, err := btcec.ParsePubKey(serializedPubKey, btcec.S256())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse pub key: %w", err)
+	}
+
+	curve := btcec.S256()
+
+	// internal key is lifted to the point with even y
+	x, y := pub.X, new(big.Int).Set(pub.Y)
+	if y.Bit(0) == 1 {
+		y.Sub(curve.P, y)
+	}
+
+	internalKey := make([]byte, 32)
+	x.FillBytes(internalKey)
+
+	tweak := new(big.Int).SetBytes(taggedHash("TapTweak", internalKey))
+	if tweak.Cmp(curve.N) >= 0 {
+		return nil, nil, fmt.Errorf("invalid taproot tweak")
+	}
+
+	tx, ty := curve.ScalarBaseMult(tweak.Bytes())
+	qx, _ := curve.Add(x, y, tx, ty)
+
+	outputKey := make([]byte, 32)
+	qx.FillBytes(outputKey)
+
+	return internalKey, outputKey, nil
+}
+
+// taprootAddress generates bech32m encoded p2tr address for a key path only spend
+func taprootAddress(serializedPubKey []byte, params *chaincfg.Params) (string, error) {
+	outputKey, err := taprootOutputKey(serializedPubKey)
+	if err != nil {
+		return "", err
+	}
+
+	program, err := bech32.ConvertBits(outputKey, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert witness program: %w", err)
+	}
+
+	// witness version 1 followed by witness program
+	return bech32mEncode(params.Bech32HRPSegwit, append([]byte{1}, program...)), nil
+}
+
+// bech32mEncode encodes 5-bit data groups with bech32m checksum
+func bech32mEncode(hrp string, data []byte) string {
+	values := append(bech32HrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ bech32mConst
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+
+	return sb.String()
+}
+
+func bech32HrpExpand(hrp string) []byte {
+	values := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	return values
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
@@ -0,0 +1,145 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxWalletJSONLength is the max length of wallet json accepted
+// by ImportWalletJSON
+const maxWalletJSONLength = 16 << 20
+
+// walletJSON holds fields of common wallet export shapes, i.e., output of
+// Bitcoin Core listdescriptors, Electrum wallet files and plain extended key
+// exports with an optional derivation path
+type walletJSON struct {
+	Descriptors []json.RawMessage `json:"descriptors"`
+	Keystore    *walletKeystore   `json:"keystore"`
+	walletKeystore
+}
+
+// walletKeystore holds extended pub key fields of Electrum keystores
+// and of plain extended key exports
+type walletKeystore struct {
+	XPub       string `json:"xpub"`
+	Derivation string `json:"derivation"`
+}
+
+// walletDescriptor is a descriptor entry of Bitcoin Core listdescriptors
+type walletDescriptor struct {
+	Desc string `json:"desc"`
+}
+
+// ImportWalletJSON decodes keys of a wallet export in JSON format. Supported
+// shapes are Bitcoin Core listdescriptors output with a descriptors array of
+// objects with desc field or of descriptor strings, a plain array of such
+// descriptor entries, unencrypted Electrum wallet files with a keystore
+// carrying xpub and derivation fields and plain objects with xpub and
+// optional derivation fields. A key is returned per descriptor, derived at
+// index 0 of its wildcard, with address of the descriptor script type.
+// Descriptors that fail to import, such as multisig descriptors or ones with
+// raw hex pub keys, are skipped and are reported per entry via a BatchError
+// returned along with keys of the other descriptors.
+// Extended pub keys are returned as is, with derivation path set to the
+// derivation field, if any, and addresses can be derived from them.
+func ImportWalletJSON(r io.Reader) ([]*Key, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxWalletJSONLength+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet json: %w", err)
+	}
+
+	if len(b) > maxWalletJSONLength {
+		return nil, fmt.Errorf("%w: wallet json exceeds %d bytes", ErrInputTooLong, maxWalletJSONLength)
+	}
+
+	// descriptors may also be exported as a plain array
+	if strings.HasPrefix(strings.TrimSpace(string(b)), "[") {
+		var entries []json.RawMessage
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode wallet json: %w", err)
+		}
+		return importWalletDescriptors(entries)
+	}
+
+	var wallet walletJSON
+	if err := json.Unmarshal(b, &wallet); err != nil {
+		return nil, fmt.Errorf("failed to decode wallet json: %w", err)
+	}
+
+	switch {
+	case len(wallet.Descriptors) > 0:
+		return importWalletDescriptors(wallet.Descriptors)
+	case wallet.Keystore != nil && len(wallet.Keystore.XPub) > 0:
+		key, err := importWalletKeystore(wallet.Keystore)
+		if err != nil {
+			return nil, err
+		}
+		return []*Key{key}, nil
+	case len(wallet.XPub) > 0:
+		key, err := importWalletKeystore(&wallet.walletKeystore)
+		if err != nil {
+			return nil, err
+		}
+		return []*Key{key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported wallet json, expected descriptors, keystore or xpub fields")
+	}
+}
+
+// importWalletDescriptors derives keys of descriptor entries, which
+// are either objects with desc field or descriptor strings, skipping
+// descriptors that fail to import
+func importWalletDescriptors(entries []json.RawMessage) ([]*Key, error) {
+	keys := make([]*Key, 0, len(entries))
+	var skipped []error
+	for i, entry := range entries {
+		var descriptor string
+		if err := json.Unmarshal(entry, &descriptor); err != nil {
+			var d walletDescriptor
+			if err := json.Unmarshal(entry, &d); err != nil {
+				skipped = append(skipped, fmt.Errorf("skipped descriptor %d: failed to decode descriptor: %w", i, err))
+				continue
+			}
+			descriptor = d.Desc
+		}
+
+		key, err := descriptorKey(descriptor, 0)
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("skipped descriptor %d: %w", i, err))
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(skipped) > 0 {
+		return keys, &BatchError{Errs: skipped, Total: len(entries)}
+	}
+
+	return keys, nil
+}
+
+// importWalletKeystore decodes extended pub key of a keystore
+func importWalletKeystore(keystore *walletKeystore) (*Key, error) {
+	key, err := ImportElectrumMasterPublicKey(keystore.XPub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import xpub: %w", err)
+	}
+
+	derivation := strings.TrimSpace(keystore.Derivation)
+	if len(derivation) == 0 {
+		return key, nil
+	}
+
+	indices, err := parseDerivationPath(derivation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse derivation: %w", err)
+	}
+
+	key.ResolvedIndices = indices
+	key.DerivationPath = formatDerivationPath(indices)
+
+	return key, nil
+}
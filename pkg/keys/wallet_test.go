@@ -0,0 +1,157 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testWalletDescriptors returns receive descriptor with checksum and key
+// origin and a multisig descriptor of account m/84h/0h/0h of mnemonic
+// abandon ... about along with the account xpub
+func testWalletDescriptors(t *testing.T) (string, string, string) {
+	account, err := New(
+		&Config{
+			Seed:           TestSeed(),
+			Network:        NetworkTypeMainnet,
+			DerivationPath: "m/84h/0h/0h",
+			AddrType:       AddrTypeLegacy,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	descriptor := fmt.Sprintf("wpkh([73c5da0a/84h/0h/0h]%s/0/*)", account.XPub)
+	checksum, err := DescriptorChecksum(descriptor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	multisig := fmt.Sprintf("sh(multi(1,%s/0/*))", account.XPub)
+
+	return descriptor + "#" + checksum, multisig, account.XPub
+}
+
+func TestImportWalletJSON_Descriptors(t *testing.T) {
+	descriptor, multisig, _ := testWalletDescriptors(t)
+
+	tests := []struct {
+		name string
+		json string
+	}{
+		{
+			name: "listdescriptors",
+			json: fmt.Sprintf(`{"wallet_name": "test", "descriptors": [
+				{"desc": %q, "timestamp": 1, "active": true, "internal": false, "range": [0, 999], "next": 0},
+				{"desc": %q, "timestamp": 1, "active": false}]}`, descriptor, multisig),
+		},
+		{
+			name: "descriptor strings",
+			json: fmt.Sprintf(`[%q, %q]`, descriptor, multisig),
+		},
+	}
+
+	for _, test := range tests {
+		keys, err := ImportWalletJSON(strings.NewReader(test.json))
+
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || len(batchErr.Errs) != 1 || !errors.Is(err, ErrUnsupportedDescriptor) {
+			t.Fatal(test.name, "expected multisig descriptor to be skipped, got", err)
+		}
+
+		if len(keys) != 1 {
+			t.Fatal(test.name, "expected 1 key, got", len(keys))
+		}
+
+		if keys[0].Addr != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+			t.Fatal(test.name, "unexpected address", keys[0].Addr)
+		}
+
+		if keys[0].DerivationPath != "m/84h/0h/0h/0/0" {
+			t.Fatal(test.name, "expected m/84h/0h/0h/0/0, got", keys[0].DerivationPath)
+		}
+	}
+}
+
+func TestImportWalletJSON_SkippedDescriptors(t *testing.T) {
+	descriptor, multisig, _ := testWalletDescriptors(t)
+
+	// pub key of private key 1
+	rawKey := "wpkh(0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798)"
+
+	input := fmt.Sprintf(`{"descriptors": [{"desc": %q}, {"desc": %q}, 42, {"desc": %q}, {"desc": "wpkh(xpub)"}]}`,
+		rawKey, multisig, descriptor)
+
+	keys, err := ImportWalletJSON(strings.NewReader(input))
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errs) != 4 || batchErr.Total != 5 {
+		t.Fatal("expected 4 of 5 descriptors to be skipped, got", err)
+	}
+
+	for i, index := range []string{"0", "1", "2", "4"} {
+		if !strings.Contains(batchErr.Errs[i].Error(), "descriptor "+index+":") {
+			t.Fatal("expected error of descriptor", index, ", got", batchErr.Errs[i])
+		}
+	}
+
+	if !errors.Is(batchErr.Errs[0], ErrUnsupportedDescriptor) || !errors.Is(batchErr.Errs[1], ErrUnsupportedDescriptor) {
+		t.Fatal("expected raw key and multisig descriptors to be unsupported, got", err)
+	}
+
+	if len(keys) != 1 || keys[0].Addr != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" {
+		t.Fatal("expected key of the supported descriptor, got", keys)
+	}
+
+	if _, err := AddressFromDescriptor(rawKey, 0); !errors.Is(err, ErrUnsupportedDescriptor) {
+		t.Fatal("expected ErrUnsupportedDescriptor, got", err)
+	}
+}
+
+func TestImportWalletJSON_Keystore(t *testing.T) {
+	_, _, xpub := testWalletDescriptors(t)
+
+	tests := []struct {
+		name           string
+		json           string
+		xpub           string
+		derivationPath string
+	}{
+		{
+			name: "electrum keystore",
+			json: fmt.Sprintf(`{"keystore": {"type": "bip32", "xpub": %q, "derivation": "m/84'/0'/0'"}, "wallet_type": "standard"}`,
+				testZpubAbandonAbout),
+			xpub:           testZpubAbandonAbout,
+			derivationPath: "m/84h/0h/0h",
+		},
+		{
+			name: "plain xpub",
+			json: fmt.Sprintf(`{"xpub": %q}`, xpub),
+			xpub: xpub,
+		},
+	}
+
+	for _, test := range tests {
+		keys, err := ImportWalletJSON(strings.NewReader(test.json))
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+
+		if len(keys) != 1 || keys[0].XPub != test.xpub {
+			t.Fatal(test.name, "expected key with xpub", test.xpub)
+		}
+
+		if keys[0].DerivationPath != test.derivationPath {
+			t.Fatal(test.name, "expected derivation path", test.derivationPath, ", got", keys[0].DerivationPath)
+		}
+	}
+}
+
+func TestImportWalletJSON_TooLong(t *testing.T) {
+	_, err := ImportWalletJSON(strings.NewReader(strings.Repeat(" ", maxWalletJSONLength+1)))
+	if !errors.Is(err, ErrInputTooLong) {
+		t.Fatal("expected ErrInputTooLong, got", err)
+	}
+}
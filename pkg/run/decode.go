@@ -10,12 +10,16 @@ import (
 	"github.com/kubetrail/bip32/pkg/keys"
 	"github.com/kubetrail/bip39/pkg/prompts"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
 func Decode(cmd *cobra.Command, args []string) error {
 	persistentFlags := getPersistentFlags(cmd)
 
+	_ = viper.BindPFlag(flags.Network, cmd.Flag(flags.Network))
+	network := viper.GetString(flags.Network)
+
 	prompt, err := prompts.Status()
 	if err != nil {
 		return fmt.Errorf("failed to get prompt status: %w", err)
@@ -67,7 +71,7 @@ func Decode(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("invalid input key length, needs to be either 38 bytes (prvKeyWif) or 82 bytes (xPrv, xPub) long")
 		}
 	case keys.KeyFormatHex:
-		key, err = keys.DecodePublicHex(keyString)
+		key, err = keys.DecodePublicHexWithNetwork(keyString, network)
 		if err != nil {
 			return fmt.Errorf("failed to decode public key hex: %w", err)
 		}
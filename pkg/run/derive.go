@@ -16,7 +16,9 @@ func Derive(cmd *cobra.Command, args []string) error {
 	persistentFlags := getPersistentFlags(cmd)
 
 	_ = viper.BindPFlag(flags.DerivationPath, cmd.Flag(flags.DerivationPath))
+	_ = viper.BindPFlag(flags.CoinType, cmd.Flag(flags.CoinType))
 	derivationPath := viper.GetString(flags.DerivationPath)
+	coinType := viper.GetString(flags.CoinType)
 
 	prompt, err := prompts.Status()
 	if err != nil {
@@ -40,7 +42,7 @@ func Derive(cmd *cobra.Command, args []string) error {
 		keyString = args[0]
 	}
 
-	key, err := keys.Derive(keyString, derivationPath)
+	key, err := keys.Derive(keyString, derivationPath, keys.WithCoinType(coinType))
 	if err != nil {
 		return fmt.Errorf("failed to derive key: %w", err)
 	}
@@ -27,6 +27,7 @@ func Gen(cmd *cobra.Command, args []string) error {
 	_ = viper.BindPFlag(flags.MnemonicLanguage, cmd.Flag(flags.MnemonicLanguage))
 	_ = viper.BindPFlag(flags.AddrType, cmd.Flag(flags.AddrType))
 	_ = viper.BindPFlag(flags.ShowAllKeys, cmd.Flag(flags.ShowAllKeys))
+	_ = viper.BindPFlag(flags.CoinType, cmd.Flag(flags.CoinType))
 
 	usePassphrase := viper.GetBool(flags.UsePassphrase)
 	skipMnemonicValidation := viper.GetBool(flags.SkipMnemonicValidation)
@@ -36,6 +37,7 @@ func Gen(cmd *cobra.Command, args []string) error {
 	language := viper.GetString(flags.MnemonicLanguage)
 	scriptType := viper.GetString(flags.AddrType)
 	showAllKeys := viper.GetBool(flags.ShowAllKeys)
+	coinType := viper.GetString(flags.CoinType)
 
 	prompt, err := prompts.Status()
 	if err != nil {
@@ -112,6 +114,7 @@ func Gen(cmd *cobra.Command, args []string) error {
 			Network:        network,
 			DerivationPath: derivationPath,
 			AddrType:       scriptType,
+			CoinType:       coinType,
 		},
 	)
 	if err != nil {